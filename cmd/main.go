@@ -15,6 +15,17 @@ import (
 	"time"
 )
 
+// orderHistorySize is how many recent orders recommendations draw on by
+// default.
+const orderHistorySize = 10_000
+
+// productTableLimit and warehouseTableLimit are how many products and
+// warehouses keep their solver tables between orders.
+const (
+	productTableLimit   = 16
+	warehouseTableLimit = 16
+)
+
 func main() {
 	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
 
@@ -22,12 +33,51 @@ func main() {
 
 	tmpl := template.Must(template.ParseFiles("templates/index.html"))
 
+	defaultPolicy, err := usecases.ParsePolicy(cfg.DefaultPolicy)
+	if err != nil {
+		slog.Error("invalid default policy", "policy", cfg.DefaultPolicy, "error", err)
+		os.Exit(1)
+	}
+
 	repo := repository.NewMemoryPackSizeRepository(cfg.PackSizes)
-	calculatePacksUseCase := usecases.NewCalculatePacksUseCase(repo)
-	packSizesUseCase := usecases.NewPackSizesUseCase(repo)
+	history := repository.NewMemoryOrderHistory(orderHistorySize)
+	tables := usecases.NewPackTableCache()
+	calculateOpts := []usecases.CalculateOption{
+		usecases.WithComputeBudget(cfg.ComputeBudget),
+		usecases.WithDefaultPolicy(defaultPolicy),
+		usecases.WithOrderHistory(history),
+		usecases.WithBatchWorkers(cfg.BatchWorkers),
+	}
+	if cfg.ResultCacheSize > 0 {
+		calculateOpts = append(calculateOpts, usecases.WithResultCache(usecases.NewResultCache(cfg.ResultCacheSize)))
+	}
+	if cfg.Coalescing {
+		calculateOpts = append(calculateOpts, usecases.WithCoalescing())
+	}
+	if cfg.ShadowPercent > 0 {
+		calculateOpts = append(calculateOpts, usecases.WithShadowSolver(cfg.ShadowPercent))
+	}
+	calculatePacksUseCase := usecases.NewCalculatePacksUseCase(repo, tables, calculateOpts...)
+	packSizesUseCase := usecases.NewPackSizesUseCase(repo, tables)
+	productRepo := repository.NewMemoryProductRepository()
+	productTables := usecases.NewPackTablesByID(productTableLimit)
+	calculateOrderUseCase := usecases.NewCalculateOrderUseCase(productRepo, calculatePacksUseCase, productTables)
+	productsUseCase := usecases.NewProductsUseCase(productRepo, productTables)
+	warehouseRepo := repository.NewMemoryWarehouseRepository()
+	warehouseTables := usecases.NewPackTablesByID(warehouseTableLimit)
+	splitOrderUseCase := usecases.NewSplitOrderUseCase(warehouseRepo, calculatePacksUseCase, warehouseTables)
+	warehousesUseCase := usecases.NewWarehousesUseCase(warehouseRepo, warehouseTables)
+	quoteUseCase := usecases.NewQuoteUseCase(calculatePacksUseCase)
+	reportUseCase := usecases.NewPackSetReportUseCase(calculatePacksUseCase)
+	recommendUseCase := usecases.NewRecommendPackSizesUseCase(calculatePacksUseCase, history)
+	simulateUseCase := usecases.NewSimulatePackSizesUseCase(calculatePacksUseCase)
 
 	handler := httphandler.NewPackCalculatorHandler(calculatePacksUseCase, packSizesUseCase)
-	router := httphandler.NewRouter(handler, tmpl)
+	orderHandler := httphandler.NewOrderHandler(calculateOrderUseCase, productsUseCase)
+	reportHandler := httphandler.NewReportHandler(reportUseCase, recommendUseCase, simulateUseCase)
+	warehouseHandler := httphandler.NewWarehouseHandler(splitOrderUseCase, warehousesUseCase)
+	quoteHandler := httphandler.NewQuoteHandler(quoteUseCase)
+	router := httphandler.NewRouter(handler, orderHandler, reportHandler, warehouseHandler, quoteHandler, tmpl)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -58,6 +108,7 @@ func main() {
 			slog.Error("graceful shutdown failed", "error", err)
 			_ = srv.Close()
 		}
+		calculatePacksUseCase.Close()
 	}
 
 	slog.Info("server stopped")
@@ -3,19 +3,33 @@ package config
 import (
 	"calculate_product_packs/internal/domain"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
-	PackSizes []domain.PackSize
-	Port      string
+	PackSizes       []domain.PackSize
+	Port            string
+	ComputeBudget   time.Duration
+	DefaultPolicy   string
+	BatchWorkers    int
+	ResultCacheSize int
+	Coalescing      bool
+	ShadowPercent   float64
 }
 
 func NewConfig() *Config {
 	return &Config{
-		PackSizes: getPackSizesFromEnv(),
-		Port:      getPortFromEnv(),
+		PackSizes:       getPackSizesFromEnv(),
+		Port:            getPortFromEnv(),
+		ComputeBudget:   getComputeBudgetFromEnv(),
+		DefaultPolicy:   getDefaultPolicyFromEnv(),
+		BatchWorkers:    getBatchWorkersFromEnv(),
+		ResultCacheSize: getResultCacheSizeFromEnv(),
+		Coalescing:      getCoalescingFromEnv(),
+		ShadowPercent:   getShadowPercentFromEnv(),
 	}
 }
 
@@ -47,3 +61,52 @@ func getPortFromEnv() string {
 	}
 	return "8080"
 }
+
+func getComputeBudgetFromEnv() time.Duration {
+	if budget, err := time.ParseDuration(os.Getenv("COMPUTE_BUDGET")); err == nil && budget >= 0 {
+		return budget
+	}
+	return 10 * time.Second
+}
+
+func getDefaultPolicyFromEnv() string {
+	if policy := os.Getenv("DEFAULT_POLICY"); policy != "" {
+		return policy
+	}
+	return "fewest-items"
+}
+
+func getBatchWorkersFromEnv() int {
+	if workers, err := strconv.Atoi(os.Getenv("BATCH_WORKERS")); err == nil && workers > 0 {
+		return workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// getResultCacheSizeFromEnv reads RESULT_CACHE_SIZE unless RESULT_CACHE
+// turns the cache off.
+func getResultCacheSizeFromEnv() int {
+	if enabled, err := strconv.ParseBool(os.Getenv("RESULT_CACHE")); err == nil && !enabled {
+		return 0
+	}
+	if size, err := strconv.Atoi(os.Getenv("RESULT_CACHE_SIZE")); err == nil && size >= 0 {
+		return size
+	}
+	return 1000
+}
+
+func getCoalescingFromEnv() bool {
+	if enabled, err := strconv.ParseBool(os.Getenv("COALESCING")); err == nil {
+		return enabled
+	}
+	return true
+}
+
+// getShadowPercentFromEnv reads SHADOW_PERCENT, the share of calculations
+// re-checked by the shadow solver, from 0 to 100.
+func getShadowPercentFromEnv() float64 {
+	if percent, err := strconv.ParseFloat(os.Getenv("SHADOW_PERCENT"), 64); err == nil && percent >= 0 && percent <= 100 {
+		return percent
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DecimalPlaces is how many fractional digits a Decimal keeps.
+const DecimalPlaces = 4
+
+// DecimalScale is one in the units a Decimal counts in.
+const DecimalScale = 10_000
+
+var decimalPattern = regexp.MustCompile(`^(-?)(\d{1,14})(?:\.(\d{1,4}))?$`)
+
+// Decimal is an exact amount with up to four fractional digits, stored as a
+// count of ten-thousandths, so prices add and multiply without float
+// rounding. It reads JSON strings or numbers and writes strings, with at
+// least two fractional digits: "12.50", "0.0125".
+type Decimal int64
+
+// ParseDecimal reads a plain decimal number such as "12.5" or "-0.0125".
+// Exponents and more than four fractional digits are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	m := decimalPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	// 14 integer digits times the scale stay within int64.
+	whole, _ := strconv.ParseInt(m[2], 10, 64)
+	frac := int64(0)
+	if m[3] != "" {
+		frac, _ = strconv.ParseInt(m[3]+strings.Repeat("0", DecimalPlaces-len(m[3])), 10, 64)
+	}
+	d := Decimal(whole*DecimalScale + frac)
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func (d Decimal) String() string {
+	sign := ""
+	u := uint64(d)
+	if d < 0 {
+		sign, u = "-", uint64(-d)
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%04d", u%DecimalScale), "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	return fmt.Sprintf("%s%d.%s", sign, u/DecimalScale, frac)
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidDecimal, data)
+		}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Decimal
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12", want: 120_000},
+		{in: "12.5", want: 125_000},
+		{in: "0.0125", want: 125},
+		{in: "-3.25", want: -32_500},
+		{in: "99999999999999.9999", want: 999_999_999_999_999_999},
+		{in: "0.00001", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "1.", wantErr: true},
+		{in: ".5", wantErr: true},
+		{in: "+1", wantErr: true},
+		{in: "100000000000000", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDecimal(tt.in)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDecimal)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecimal_String(t *testing.T) {
+	assert.Equal(t, "0.00", Decimal(0).String())
+	assert.Equal(t, "12.50", Decimal(125_000).String())
+	assert.Equal(t, "0.0125", Decimal(125).String())
+	assert.Equal(t, "0.001", Decimal(10).String())
+	assert.Equal(t, "-3.25", Decimal(-32_500).String())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var prices []Decimal
+	require.NoError(t, json.Unmarshal([]byte(`["12.5", 0.1, 3]`), &prices))
+	assert.Equal(t, []Decimal{125_000, 1000, 30_000}, prices)
+
+	out, err := json.Marshal(prices)
+	require.NoError(t, err)
+	assert.Equal(t, `["12.50","0.10","3.00"]`, string(out))
+
+	var d Decimal
+	assert.ErrorIs(t, json.Unmarshal([]byte(`1.00001`), &d), ErrInvalidDecimal)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"abc"`), &d), ErrInvalidDecimal)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrOrderSizePositive = errors.New("order size must be greater than 0")
@@ -8,4 +11,94 @@ var (
 	ErrEmptyPackSizes    = errors.New("pack sizes cannot be empty")
 	ErrInvalidPackSize   = errors.New("invalid pack size")
 	ErrTooManyPackSizes  = errors.New("too many pack sizes")
+
+	ErrInvalidStock      = errors.New("invalid stock entry")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrOrderTooLarge     = errors.New("order too large for a constrained calculation")
+	ErrTotalOverflow     = errors.New("order total exceeds the 64-bit range")
+
+	ErrInvalidPackCost  = errors.New("invalid pack cost")
+	ErrMissingPackCosts = errors.New("some pack sizes have no cost")
+	ErrInvalidPolicy    = errors.New("invalid policy")
+
+	ErrInvalidPackDimensions = errors.New("invalid pack dimensions")
+	ErrMissingPackDimensions = errors.New("some pack sizes have no dimensions")
+	ErrInvalidParcelLimits   = errors.New("parcel limits must be non-negative and set at least one of maxWeight and maxVolume")
+	ErrPackExceedsParcel     = errors.New("pack does not fit in a parcel")
+
+	ErrInvalidPackaging = errors.New("invalid packaging hierarchy")
+
+	ErrInvalidDecimal    = errors.New("invalid decimal")
+	ErrInvalidPricing    = errors.New("invalid pricing")
+	ErrMissingPackPrices = errors.New("some pack sizes have no price")
+	ErrQuoteTooLarge     = errors.New("quote exceeds the largest supported amount")
+
+	ErrInvalidPackConstraint       = errors.New("invalid pack constraint")
+	ErrUnsatisfiablePackConstraint = errors.New("pack constraints cannot be satisfied")
+	ErrPackConstraintsUnmet        = errors.New("order cannot meet the pack constraints")
+
+	ErrInvalidOvershoot       = errors.New("max overshoot must be non-negative and at most 1000%")
+	ErrOvershootLimitExceeded = errors.New("no packing fits within the overshoot limit")
+
+	ErrInvalidProductID   = errors.New("product ID must be 1-64 letters, digits, '.', '_' or '-'")
+	ErrProductNotFound    = errors.New("product not found")
+	ErrEmptyOrder         = errors.New("order must have at least one line")
+	ErrTooManyOrderLines  = errors.New("too many order lines")
+	ErrTooManyBatchOrders = errors.New("too many orders in batch")
+
+	ErrInvalidWarehouseID         = errors.New("warehouse ID must be 1-64 letters, digits, '.', '_' or '-'")
+	ErrWarehouseNotFound          = errors.New("warehouse not found")
+	ErrNoWarehouses               = errors.New("no warehouses to ship from")
+	ErrInvalidWarehousePreference = errors.New("warehouse preferences must name distinct warehouses with non-negative costs")
+
+	ErrEmptyWorkload         = errors.New("workload has no orders")
+	ErrInvalidWorkload       = errors.New("workload must have at most 10000 orders of 1-100000 items, weighted 1-1000000")
+	ErrInvalidRecommendation = errors.New("count must be 1-20 and sizes must satisfy 1 <= minSize <= maxSize <= 1000000")
+
+	ErrInvalidAlternativeCount = errors.New("alternative count must be between 1 and 10")
+	ErrInvalidReportRange      = errors.New("report range must be between 1 and 100000")
+
+	ErrComputeBudgetExceeded = errors.New("calculation exceeded its compute budget")
+	ErrCalculationCanceled   = errors.New("calculation canceled")
+
+	ErrInvalidPacking           = errors.New("packing does not cover the order with the pack sizes")
+	ErrPackingNotOptimal        = errors.New("packing is not optimal")
+	ErrVerificationInconclusive = errors.New("verification ran out of its search budget")
 )
+
+// InsufficientStockError reports an order that the available stock cannot
+// cover even when everything is shipped. Packs lists that stock, which is the
+// most that can be sent towards the order.
+type InsufficientStockError struct {
+	OrderSize int64        `json:"orderSize"`
+	Available int64        `json:"available"`
+	Shortfall int64        `json:"shortfall"`
+	Packs     []PackResult `json:"packs"`
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("%s: %d of %d items available", ErrInsufficientStock, e.Available, e.OrderSize)
+}
+
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
+
+// OvershootError reports an order that no packing covers within the allowed
+// overshoot. NearestBelow and NearestAbove are the closest totals that can be
+// shipped on either side of the order; NearestBelow is 0 when nothing smaller
+// can.
+type OvershootError struct {
+	OrderSize    int64 `json:"orderSize"`
+	MaxOvershoot int64 `json:"maxOvershoot"`
+	NearestBelow int64 `json:"nearestBelow"`
+	NearestAbove int64 `json:"nearestAbove"`
+}
+
+func (e *OvershootError) Error() string {
+	return fmt.Sprintf("%s: nearest totals are %d and %d", ErrOvershootLimitExceeded, e.NearestBelow, e.NearestAbove)
+}
+
+func (e *OvershootError) Is(target error) bool {
+	return target == ErrOvershootLimitExceeded
+}
@@ -0,0 +1,60 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/domain (interfaces: OrderHistoryRepository)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderHistoryRepository is a mock of OrderHistoryRepository interface.
+type MockOrderHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderHistoryRepositoryMockRecorder
+}
+
+// MockOrderHistoryRepositoryMockRecorder is the mock recorder for MockOrderHistoryRepository.
+type MockOrderHistoryRepositoryMockRecorder struct {
+	mock *MockOrderHistoryRepository
+}
+
+// NewMockOrderHistoryRepository creates a new mock instance.
+func NewMockOrderHistoryRepository(ctrl *gomock.Controller) *MockOrderHistoryRepository {
+	mock := &MockOrderHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderHistoryRepository) EXPECT() *MockOrderHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetOrders mocks base method.
+func (m *MockOrderHistoryRepository) GetOrders() []domain.WeightedOrder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders")
+	ret0, _ := ret[0].([]domain.WeightedOrder)
+	return ret0
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockOrderHistoryRepositoryMockRecorder) GetOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderHistoryRepository)(nil).GetOrders))
+}
+
+// RecordOrder mocks base method.
+func (m *MockOrderHistoryRepository) RecordOrder(arg0 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordOrder", arg0)
+}
+
+// RecordOrder indicates an expected call of RecordOrder.
+func (mr *MockOrderHistoryRepositoryMockRecorder) RecordOrder(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOrder", reflect.TypeOf((*MockOrderHistoryRepository)(nil).RecordOrder), arg0)
+}
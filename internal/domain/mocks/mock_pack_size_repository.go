@@ -33,6 +33,62 @@ func (m *MockPackSizeRepository) EXPECT() *MockPackSizeRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetConstraints mocks base method.
+func (m *MockPackSizeRepository) GetConstraints() []domain.PackConstraint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConstraints")
+	ret0, _ := ret[0].([]domain.PackConstraint)
+	return ret0
+}
+
+// GetConstraints indicates an expected call of GetConstraints.
+func (mr *MockPackSizeRepositoryMockRecorder) GetConstraints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConstraints", reflect.TypeOf((*MockPackSizeRepository)(nil).GetConstraints))
+}
+
+// GetCosts mocks base method.
+func (m *MockPackSizeRepository) GetCosts() []domain.PackCost {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCosts")
+	ret0, _ := ret[0].([]domain.PackCost)
+	return ret0
+}
+
+// GetCosts indicates an expected call of GetCosts.
+func (mr *MockPackSizeRepositoryMockRecorder) GetCosts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCosts", reflect.TypeOf((*MockPackSizeRepository)(nil).GetCosts))
+}
+
+// GetDimensions mocks base method.
+func (m *MockPackSizeRepository) GetDimensions() []domain.PackDimensions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDimensions")
+	ret0, _ := ret[0].([]domain.PackDimensions)
+	return ret0
+}
+
+// GetDimensions indicates an expected call of GetDimensions.
+func (mr *MockPackSizeRepositoryMockRecorder) GetDimensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDimensions", reflect.TypeOf((*MockPackSizeRepository)(nil).GetDimensions))
+}
+
+// GetHierarchies mocks base method.
+func (m *MockPackSizeRepository) GetHierarchies() []domain.PackHierarchy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHierarchies")
+	ret0, _ := ret[0].([]domain.PackHierarchy)
+	return ret0
+}
+
+// GetHierarchies indicates an expected call of GetHierarchies.
+func (mr *MockPackSizeRepositoryMockRecorder) GetHierarchies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHierarchies", reflect.TypeOf((*MockPackSizeRepository)(nil).GetHierarchies))
+}
+
 // GetPackSizes mocks base method.
 func (m *MockPackSizeRepository) GetPackSizes() []domain.PackSize {
 	m.ctrl.T.Helper()
@@ -47,6 +103,104 @@ func (mr *MockPackSizeRepositoryMockRecorder) GetPackSizes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackSizes", reflect.TypeOf((*MockPackSizeRepository)(nil).GetPackSizes))
 }
 
+// GetPricing mocks base method.
+func (m *MockPackSizeRepository) GetPricing() domain.Pricing {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPricing")
+	ret0, _ := ret[0].(domain.Pricing)
+	return ret0
+}
+
+// GetPricing indicates an expected call of GetPricing.
+func (mr *MockPackSizeRepositoryMockRecorder) GetPricing() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPricing", reflect.TypeOf((*MockPackSizeRepository)(nil).GetPricing))
+}
+
+// GetStock mocks base method.
+func (m *MockPackSizeRepository) GetStock() []domain.PackStock {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock")
+	ret0, _ := ret[0].([]domain.PackStock)
+	return ret0
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockPackSizeRepositoryMockRecorder) GetStock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockPackSizeRepository)(nil).GetStock))
+}
+
+// PackSetVersion mocks base method.
+func (m *MockPackSizeRepository) PackSetVersion() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PackSetVersion")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// PackSetVersion indicates an expected call of PackSetVersion.
+func (mr *MockPackSizeRepositoryMockRecorder) PackSetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackSetVersion", reflect.TypeOf((*MockPackSizeRepository)(nil).PackSetVersion))
+}
+
+// UpdateConstraints mocks base method.
+func (m *MockPackSizeRepository) UpdateConstraints(arg0 []domain.PackConstraint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConstraints", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConstraints indicates an expected call of UpdateConstraints.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdateConstraints(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConstraints", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdateConstraints), arg0)
+}
+
+// UpdateCosts mocks base method.
+func (m *MockPackSizeRepository) UpdateCosts(arg0 []domain.PackCost) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCosts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCosts indicates an expected call of UpdateCosts.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdateCosts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCosts", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdateCosts), arg0)
+}
+
+// UpdateDimensions mocks base method.
+func (m *MockPackSizeRepository) UpdateDimensions(arg0 []domain.PackDimensions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDimensions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDimensions indicates an expected call of UpdateDimensions.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdateDimensions(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDimensions", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdateDimensions), arg0)
+}
+
+// UpdateHierarchies mocks base method.
+func (m *MockPackSizeRepository) UpdateHierarchies(arg0 []domain.PackHierarchy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHierarchies", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHierarchies indicates an expected call of UpdateHierarchies.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdateHierarchies(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHierarchies", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdateHierarchies), arg0)
+}
+
 // UpdatePackSizes mocks base method.
 func (m *MockPackSizeRepository) UpdatePackSizes(arg0 []domain.PackSize) error {
 	m.ctrl.T.Helper()
@@ -60,3 +214,31 @@ func (mr *MockPackSizeRepositoryMockRecorder) UpdatePackSizes(arg0 any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePackSizes", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdatePackSizes), arg0)
 }
+
+// UpdatePricing mocks base method.
+func (m *MockPackSizeRepository) UpdatePricing(arg0 domain.Pricing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePricing", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePricing indicates an expected call of UpdatePricing.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdatePricing(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePricing", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdatePricing), arg0)
+}
+
+// UpdateStock mocks base method.
+func (m *MockPackSizeRepository) UpdateStock(arg0 []domain.PackStock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockPackSizeRepositoryMockRecorder) UpdateStock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockPackSizeRepository)(nil).UpdateStock), arg0)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/domain (interfaces: WarehouseRepository)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWarehouseRepository is a mock of WarehouseRepository interface.
+type MockWarehouseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarehouseRepositoryMockRecorder
+}
+
+// MockWarehouseRepositoryMockRecorder is the mock recorder for MockWarehouseRepository.
+type MockWarehouseRepositoryMockRecorder struct {
+	mock *MockWarehouseRepository
+}
+
+// NewMockWarehouseRepository creates a new mock instance.
+func NewMockWarehouseRepository(ctrl *gomock.Controller) *MockWarehouseRepository {
+	mock := &MockWarehouseRepository{ctrl: ctrl}
+	mock.recorder = &MockWarehouseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarehouseRepository) EXPECT() *MockWarehouseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteWarehouse mocks base method.
+func (m *MockWarehouseRepository) DeleteWarehouse(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWarehouse indicates an expected call of DeleteWarehouse.
+func (mr *MockWarehouseRepositoryMockRecorder) DeleteWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWarehouse", reflect.TypeOf((*MockWarehouseRepository)(nil).DeleteWarehouse), arg0)
+}
+
+// GetWarehouse mocks base method.
+func (m *MockWarehouseRepository) GetWarehouse(arg0 string) (domain.Warehouse, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouse", arg0)
+	ret0, _ := ret[0].(domain.Warehouse)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetWarehouse indicates an expected call of GetWarehouse.
+func (mr *MockWarehouseRepositoryMockRecorder) GetWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouse", reflect.TypeOf((*MockWarehouseRepository)(nil).GetWarehouse), arg0)
+}
+
+// GetWarehouses mocks base method.
+func (m *MockWarehouseRepository) GetWarehouses() []domain.Warehouse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouses")
+	ret0, _ := ret[0].([]domain.Warehouse)
+	return ret0
+}
+
+// GetWarehouses indicates an expected call of GetWarehouses.
+func (mr *MockWarehouseRepositoryMockRecorder) GetWarehouses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouses", reflect.TypeOf((*MockWarehouseRepository)(nil).GetWarehouses))
+}
+
+// SaveWarehouse mocks base method.
+func (m *MockWarehouseRepository) SaveWarehouse(arg0 domain.Warehouse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWarehouse indicates an expected call of SaveWarehouse.
+func (mr *MockWarehouseRepositoryMockRecorder) SaveWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWarehouse", reflect.TypeOf((*MockWarehouseRepository)(nil).SaveWarehouse), arg0)
+}
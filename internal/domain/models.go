@@ -2,13 +2,491 @@ package domain
 
 type PackSize int
 
+// PackResult is a number of packs of one size. Pack counts, like order sizes
+// and totals, are int64 on every platform so that bulk orders can use the
+// full 64-bit range; totals that would not fit fail with ErrTotalOverflow.
 type PackResult struct {
 	Size  PackSize `json:"size"`
-	Count int      `json:"count"`
+	Count int64    `json:"count"`
+}
+
+// PackStock is how many packs of a size are available to ship.
+type PackStock struct {
+	Size      PackSize `json:"size"`
+	Available int      `json:"available"`
+}
+
+// PackCost is what one pack of a size costs, in minor currency units.
+type PackCost struct {
+	Size PackSize `json:"size"`
+	Cost int64    `json:"cost"`
+}
+
+// PackDimensions is the weight in grams and the outer size in centimetres of
+// one pack of a size.
+type PackDimensions struct {
+	Size   PackSize `json:"size"`
+	Weight int64    `json:"weight"`
+	Length int64    `json:"length"`
+	Width  int64    `json:"width"`
+	Height int64    `json:"height"`
+}
+
+// Volume is the space the pack takes in a parcel, in cubic centimetres.
+func (d PackDimensions) Volume() int64 {
+	return d.Length * d.Width * d.Height
+}
+
+// PackagingLevel is a unit of packaging that holds Contains units of the level
+// below it, or packs for the first level.
+type PackagingLevel struct {
+	Name     string `json:"name"`
+	Contains int64  `json:"contains"`
+}
+
+// PackHierarchy is how packs of a size consolidate, from the smallest level
+// up: e.g. cases of 12 packs, then pallets of 40 cases.
+type PackHierarchy struct {
+	Size   PackSize         `json:"size"`
+	Levels []PackagingLevel `json:"levels"`
+}
+
+// PackConstraint limits how many packs of a size one order may use. It
+// applies to orders of more than OrdersOver items, or to every order when
+// OrdersOver is 0. Max is nil for no upper limit.
+type PackConstraint struct {
+	Size       PackSize `json:"size"`
+	Min        int      `json:"min"`
+	Max        *int     `json:"max,omitempty"`
+	OrdersOver int64    `json:"ordersOver,omitempty"`
+}
+
+// CalculateOptions selects how a calculation treats the pack set.
+type CalculateOptions struct {
+	// RespectStock limits every size with a stock entry to its available
+	// packs. Sizes without one are treated as unlimited.
+	RespectStock bool
+	// Policy names the ranking policy; empty uses the server default.
+	Policy string
+	// ExactOnly refuses any overshoot.
+	ExactOnly bool
+	// MaxOvershoot caps the items shipped beyond the order; nil for no cap.
+	MaxOvershoot *int64
+	// MaxOvershootPercent caps them as a percentage of the order; nil for no
+	// cap. The tightest of the caps applies.
+	MaxOvershootPercent *int
+}
+
+// ParcelLimits are a carrier's per-parcel limits, in grams and cubic
+// centimetres. Zero leaves that dimension unlimited.
+type ParcelLimits struct {
+	MaxWeight int64
+	MaxVolume int64
+}
+
+// Parcel is Count identical parcels, each holding Packs.
+type Parcel struct {
+	Count  int64        `json:"count"`
+	Packs  []PackResult `json:"packs"`
+	Weight int64        `json:"weight"`
+	Volume int64        `json:"volume"`
+}
+
+// ShipmentPlan is the packing of an order split into parcels. Optimal reports
+// whether ParcelCount is proven the fewest; packings too large to search
+// exactly are split by a heuristic that may use a few more.
+type ShipmentPlan struct {
+	Packs       []PackResult `json:"packs"`
+	ParcelCount int64        `json:"parcelCount"`
+	Optimal     bool         `json:"optimal"`
+	Parcels     []Parcel     `json:"parcels"`
+}
+
+// PackagingUnit is a number of full units of one packaging level.
+type PackagingUnit struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Consolidation is how the packs of one size fill their packaging levels:
+// full units from the largest level down, then the packs left loose.
+type Consolidation struct {
+	Size       PackSize        `json:"size"`
+	Packs      int64           `json:"packs"`
+	Units      []PackagingUnit `json:"units"`
+	LoosePacks int64           `json:"loosePacks"`
+}
+
+// PackagingPlan is the packing of an order consolidated per size.
+type PackagingPlan struct {
+	Packs         []PackResult    `json:"packs"`
+	Consolidation []Consolidation `json:"consolidation"`
+}
+
+// BatchResult is the packing of one order of a batch, or the error that
+// stopped it. Index is the order's position in the batch.
+type BatchResult struct {
+	Index     int
+	OrderSize int64
+	Packs     []PackResult
+	Err       error
+}
+
+// Alternative is one way of packing an order, with its totals so packings can
+// be compared without recomputing them.
+type Alternative struct {
+	Packs      []PackResult `json:"packs"`
+	TotalItems int64        `json:"totalItems"`
+	PackCount  int64        `json:"packCount"`
+}
+
+// PackSetAnalysis describes which totals a pack set can ship. Only multiples
+// of GCD can be shipped; of those, every one above FrobeniusNumber can.
+type PackSetAnalysis struct {
+	PackSizes []PackSize `json:"packSizes"`
+	GCD       int        `json:"gcd"`
+	// FrobeniusNumber is the largest multiple of GCD no packing makes
+	// exactly, or -1 if every one can be made. For coprime sizes it is the
+	// largest quantity that always overshoots.
+	FrobeniusNumber int64 `json:"frobeniusNumber"`
+	// RedundantSizes are the sizes smaller sizes add up to exactly. Without
+	// them the pack set ships the same totals, so every order keeps its fewest
+	// items; only the number of packs can grow.
+	RedundantSizes []PackSize `json:"redundantSizes"`
+}
+
+// PackSetReport summarizes how a pack set serves every order from 1 to UpTo
+// under the default rules, for planners comparing pack sets.
+type PackSetReport struct {
+	PackSizes []PackSize `json:"packSizes"`
+	UpTo      int64      `json:"upTo"`
+	// ExactOrders is how many of the orders ship without overshoot.
+	ExactOrders      int64   `json:"exactOrders"`
+	AverageOvershoot float64 `json:"averageOvershoot"`
+	WorstOvershoot   int64   `json:"worstOvershoot"`
+	// WorstOrderSize is the smallest order with the worst overshoot.
+	WorstOrderSize int64 `json:"worstOrderSize"`
+	// UnusedSizes appear in none of the orders' packings.
+	UnusedSizes []PackSize   `json:"unusedSizes"`
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+// Breakpoint is an order size from which the optimal packing changes. It holds
+// for every order up to TotalItems, so the overshoot falls by one per item
+// until the next breakpoint.
+type Breakpoint struct {
+	OrderSize  int64        `json:"orderSize"`
+	Packs      []PackResult `json:"packs"`
+	TotalItems int64        `json:"totalItems"`
+	PackCount  int64        `json:"packCount"`
+}
+
+// BreakpointTable lists the packing of every order from 1 to UpTo, one
+// interval of order sizes per packing.
+type BreakpointTable struct {
+	PackSizes      []PackSize           `json:"packSizes"`
+	PackSetVersion uint64               `json:"packSetVersion"`
+	UpTo           int64                `json:"upTo"`
+	Intervals      []BreakpointInterval `json:"intervals"`
+}
+
+// BreakpointInterval is a range of order sizes that all ship the same packs.
+// TotalItems may exceed To when the table stops inside the interval.
+type BreakpointInterval struct {
+	From       int64        `json:"from"`
+	To         int64        `json:"to"`
+	Packs      []PackResult `json:"packs"`
+	TotalItems int64        `json:"totalItems"`
+	PackCount  int64        `json:"packCount"`
+}
+
+// ResultCacheStats reports on the cache of calculated packings. HitRate is
+// Hits over all lookups, 0 before the first.
+type ResultCacheStats struct {
+	Enabled   bool    `json:"enabled"`
+	Capacity  int     `json:"capacity"`
+	Size      int     `json:"size"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hitRate"`
+}
+
+// CoalescingStats reports on identical concurrent calculations sharing one
+// run. Deduplicated counts the Calls that joined a calculation already
+// running rather than starting their own.
+type CoalescingStats struct {
+	Enabled      bool   `json:"enabled"`
+	Calls        uint64 `json:"calls"`
+	Deduplicated uint64 `json:"deduplicated"`
+	InFlight     int    `json:"inFlight"`
+}
+
+// ShadowStats reports on live packings re-checked by an independent solver.
+// Checked counts the finished checks: Disagreements found a better or invalid
+// packing, Inconclusive ran out of search budget, and the rest agreed.
+// Skipped counts samples dropped while too many checks were running.
+type ShadowStats struct {
+	Enabled       bool    `json:"enabled"`
+	Percent       float64 `json:"percent"`
+	Checked       uint64  `json:"checked"`
+	Disagreements uint64  `json:"disagreements"`
+	Inconclusive  uint64  `json:"inconclusive"`
+	Skipped       uint64  `json:"skipped"`
+}
+
+// CalculationStats reports on how calculations were served, under the
+// current PackSetVersion.
+type CalculationStats struct {
+	PackSetVersion uint64           `json:"packSetVersion"`
+	ResultCache    ResultCacheStats `json:"resultCache"`
+	Coalescing     CoalescingStats  `json:"coalescing"`
+	Shadow         ShadowStats      `json:"shadow"`
+}
+
+// WeightedOrder is an order size and how often it occurs in a workload.
+type WeightedOrder struct {
+	OrderSize int64 `json:"orderSize"`
+	Weight    int64 `json:"weight"`
+}
+
+// RecommendationRequest asks for at most Count pack sizes between MinSize and
+// MaxSize that best serve Orders, or the recorded order history when Orders is
+// empty.
+type RecommendationRequest struct {
+	Orders  []WeightedOrder `json:"orders"`
+	Count   int             `json:"count"`
+	MinSize PackSize        `json:"minSize"`
+	MaxSize PackSize        `json:"maxSize"`
+}
+
+// PackSetScore totals a workload under a pack set, counting every order by
+// its weight. Packs are the packs used of each size.
+type PackSetScore struct {
+	PackSizes  []PackSize   `json:"packSizes"`
+	TotalItems int64        `json:"totalItems"`
+	Overshoot  int64        `json:"overshoot"`
+	PackCount  int64        `json:"packCount"`
+	Packs      []PackResult `json:"packs"`
+}
+
+// Recommendation is the pack set found for a workload, scored next to the
+// current one. Current is nil when no pack sizes are stored; the savings are
+// negative where the recommendation does worse.
+type Recommendation struct {
+	Recommended    PackSetScore  `json:"recommended"`
+	Current        *PackSetScore `json:"current"`
+	OvershootSaved int64         `json:"overshootSaved"`
+	PacksSaved     int64         `json:"packsSaved"`
+}
+
+// OrderRange is every order size from From to To, each weighted Weight.
+type OrderRange struct {
+	From   int64 `json:"from"`
+	To     int64 `json:"to"`
+	Weight int64 `json:"weight"`
+}
+
+// SimulationRequest is a proposed pack set and the workload to try it on:
+// Orders, Range or both. Weights default to 1.
+type SimulationRequest struct {
+	PackSizes []PackSize      `json:"packSizes"`
+	Orders    []WeightedOrder `json:"orders"`
+	Range     *OrderRange     `json:"range"`
+}
+
+// Simulation compares a workload under the current and a proposed pack set.
+// Current is nil when no pack sizes are stored.
+type Simulation struct {
+	Current  *PackSetScore `json:"current"`
+	Proposed PackSetScore  `json:"proposed"`
+	// ChangedOrders are the order sizes the proposal packs differently,
+	// ascending.
+	ChangedOrders []int64 `json:"changedOrders"`
+}
+
+// Rules that can decide a calculation, from the first to the last applied.
+const (
+	RuleFewestItems = "fewest-items"
+	RuleFewestPacks = "fewest-packs"
+	RuleTieBreak    = "tie-break"
+)
+
+// Calculation is a packing together with the figures that explain it.
+type Calculation struct {
+	Packs      []PackResult `json:"packs"`
+	TotalItems int64        `json:"totalItems"`
+	Overshoot  int64        `json:"overshoot"`
+	PackCount  int64        `json:"packCount"`
+	// Cost is the summed pack cost, reported for policies that rank by it.
+	Cost   int64  `json:"cost,omitempty"`
+	Policy string `json:"policy"`
+	// DecidedBy is the last rule needed to single out the packing: fewest
+	// items when no other packing ships that total, fewest packs when others
+	// do but need more packs, tie-break when some need just as many. Policies
+	// that do not rank by items first report their own name.
+	DecidedBy string `json:"decidedBy"`
+	// LargeOrderShortcut reports whether the order lies beyond the amounts
+	// the solver tables store, so the largest packs were pre-allocated and
+	// only the remainder was looked up.
+	LargeOrderShortcut bool   `json:"largeOrderShortcut"`
+	PackSetVersion     uint64 `json:"packSetVersion"`
+}
+
+// Product is a catalog entry with its own pack sizes.
+type Product struct {
+	ID        string     `json:"id"`
+	PackSizes []PackSize `json:"packSizes"`
+}
+
+// OrderLine asks for a quantity of one product.
+type OrderLine struct {
+	ProductID string `json:"productId"`
+	Quantity  int64  `json:"quantity"`
+}
+
+// LineResult is the packing of one order line.
+type LineResult struct {
+	ProductID  string       `json:"productId"`
+	Quantity   int64        `json:"quantity"`
+	Packs      []PackResult `json:"packs"`
+	TotalItems int64        `json:"totalItems"`
+	Overshoot  int64        `json:"overshoot"`
+	PackCount  int64        `json:"packCount"`
+}
+
+// OrderResult is the packing of a multi-line order with its totals.
+type OrderResult struct {
+	Lines      []LineResult `json:"lines"`
+	TotalItems int64        `json:"totalItems"`
+	Overshoot  int64        `json:"overshoot"`
+	PackCount  int64        `json:"packCount"`
+}
+
+// Pricing is what customers pay for packs, in one currency.
+type Pricing struct {
+	// Currency is an ISO 4217 code such as "EUR".
+	Currency string      `json:"currency"`
+	Prices   []PackPrice `json:"prices"`
+}
+
+// PackPrice is the price of a pack size: its items at the unit price plus a
+// surcharge per pack, less the discount of the largest tier the packs of the
+// size reach.
+type PackPrice struct {
+	Size          PackSize       `json:"size"`
+	UnitPrice     Decimal        `json:"unitPrice"`
+	Surcharge     Decimal        `json:"surcharge"`
+	DiscountTiers []DiscountTier `json:"discountTiers"`
+}
+
+// DiscountTier takes Percent off the price of MinPacks or more packs of a
+// size.
+type DiscountTier struct {
+	MinPacks int64   `json:"minPacks"`
+	Percent  Decimal `json:"percent"`
+}
+
+// QuoteLine prices the packs of one size. Amount is the items at the unit
+// price plus the surcharges; Total is Amount less Discount.
+type QuoteLine struct {
+	Size            PackSize `json:"size"`
+	Count           int64    `json:"count"`
+	Items           int64    `json:"items"`
+	UnitPrice       Decimal  `json:"unitPrice"`
+	Surcharge       Decimal  `json:"surcharge"`
+	Amount          Decimal  `json:"amount"`
+	DiscountPercent Decimal  `json:"discountPercent"`
+	Discount        Decimal  `json:"discount"`
+	Total           Decimal  `json:"total"`
+}
+
+// Quote prices the packing of an order. Amounts are rounded to the cent.
+type Quote struct {
+	OrderSize int64       `json:"orderSize"`
+	Currency  string      `json:"currency"`
+	Lines     []QuoteLine `json:"lines"`
+	Subtotal  Decimal     `json:"subtotal"`
+	Discount  Decimal     `json:"discount"`
+	Total     Decimal     `json:"total"`
+}
+
+// Warehouse ships from its own pack sizes and stock. Like the main stock,
+// Stock caps the packs of the sizes it lists and leaves the others unlimited.
+type Warehouse struct {
+	ID        string      `json:"id"`
+	PackSizes []PackSize  `json:"packSizes"`
+	Stock     []PackStock `json:"stock"`
+}
+
+// WarehousePreference ranks a warehouse for splitting an order: lower cost
+// first, then the order given.
+type WarehousePreference struct {
+	WarehouseID string `json:"warehouseId"`
+	Cost        int64  `json:"cost"`
+}
+
+// WarehouseAllocation is the part of an order one warehouse ships.
+type WarehouseAllocation struct {
+	WarehouseID string       `json:"warehouseId"`
+	Quantity    int64        `json:"quantity"`
+	Packs       []PackResult `json:"packs"`
+	TotalItems  int64        `json:"totalItems"`
+	Overshoot   int64        `json:"overshoot"`
+	PackCount   int64        `json:"packCount"`
+}
+
+// WarehouseSplit is an order split across warehouses with its totals.
+type WarehouseSplit struct {
+	Quantity    int64                 `json:"quantity"`
+	Allocations []WarehouseAllocation `json:"allocations"`
+	TotalItems  int64                 `json:"totalItems"`
+	Overshoot   int64                 `json:"overshoot"`
+	PackCount   int64                 `json:"packCount"`
 }
 
 //go:generate mockgen -destination=mocks/mock_pack_size_repository.go -package=mocks calculate_product_packs/internal/domain PackSizeRepository
 type PackSizeRepository interface {
 	GetPackSizes() []PackSize
 	UpdatePackSizes(sizes []PackSize) error
+	GetStock() []PackStock
+	UpdateStock(stock []PackStock) error
+	GetCosts() []PackCost
+	UpdateCosts(costs []PackCost) error
+	GetConstraints() []PackConstraint
+	UpdateConstraints(constraints []PackConstraint) error
+	GetDimensions() []PackDimensions
+	UpdateDimensions(dimensions []PackDimensions) error
+	GetHierarchies() []PackHierarchy
+	UpdateHierarchies(hierarchies []PackHierarchy) error
+	GetPricing() Pricing
+	UpdatePricing(pricing Pricing) error
+	// PackSetVersion changes with every update of the pack sizes or of the
+	// stock, costs and constraints calculations read, so a packing computed
+	// under one version holds until it changes.
+	PackSetVersion() uint64
+}
+
+//go:generate mockgen -destination=mocks/mock_product_repository.go -package=mocks calculate_product_packs/internal/domain ProductRepository
+type ProductRepository interface {
+	GetProducts() []Product
+	GetProduct(id string) (Product, bool)
+	SaveProduct(product Product) error
+	DeleteProduct(id string) error
+}
+
+//go:generate mockgen -destination=mocks/mock_warehouse_repository.go -package=mocks calculate_product_packs/internal/domain WarehouseRepository
+type WarehouseRepository interface {
+	GetWarehouses() []Warehouse
+	GetWarehouse(id string) (Warehouse, bool)
+	SaveWarehouse(warehouse Warehouse) error
+	DeleteWarehouse(id string) error
+}
+
+//go:generate mockgen -destination=mocks/mock_order_history_repository.go -package=mocks calculate_product_packs/internal/domain OrderHistoryRepository
+type OrderHistoryRepository interface {
+	RecordOrder(orderSize int64)
+	// GetOrders returns the recorded sizes ascending, weighted by how often
+	// they were recorded.
+	GetOrders() []WeightedOrder
 }
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sort"
+	"sync"
+)
+
+// MemoryOrderHistory keeps the most recent order sizes in a ring buffer, so
+// the workload it reports follows recent demand.
+type MemoryOrderHistory struct {
+	mu     sync.Mutex
+	orders []int64
+	next   int
+}
+
+func NewMemoryOrderHistory(capacity int) domain.OrderHistoryRepository {
+	return &MemoryOrderHistory{orders: make([]int64, 0, capacity)}
+}
+
+func (h *MemoryOrderHistory) RecordOrder(orderSize int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.orders) < cap(h.orders) {
+		h.orders = append(h.orders, orderSize)
+		return
+	}
+	h.orders[h.next] = orderSize
+	h.next = (h.next + 1) % len(h.orders)
+}
+
+func (h *MemoryOrderHistory) GetOrders() []domain.WeightedOrder {
+	h.mu.Lock()
+	weights := make(map[int64]int64, len(h.orders))
+	for _, size := range h.orders {
+		weights[size]++
+	}
+	h.mu.Unlock()
+
+	orders := make([]domain.WeightedOrder, 0, len(weights))
+	for size, weight := range weights {
+		orders = append(orders, domain.WeightedOrder{OrderSize: size, Weight: weight})
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderSize < orders[j].OrderSize })
+	return orders
+}
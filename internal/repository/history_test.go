@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryOrderHistory_GetOrders(t *testing.T) {
+	history := NewMemoryOrderHistory(4)
+	assert.Empty(t, history.GetOrders())
+
+	for _, size := range []int64{500, 250, 500} {
+		history.RecordOrder(size)
+	}
+	assert.Equal(t, []domain.WeightedOrder{{OrderSize: 250, Weight: 1}, {OrderSize: 500, Weight: 2}}, history.GetOrders())
+
+	// Beyond the capacity the oldest orders are dropped.
+	for _, size := range []int64{750, 1000, 1000} {
+		history.RecordOrder(size)
+	}
+	assert.Equal(t, []domain.WeightedOrder{{OrderSize: 500, Weight: 1}, {OrderSize: 750, Weight: 1}, {OrderSize: 1000, Weight: 2}}, history.GetOrders())
+}
+
+func TestMemoryOrderHistory_ConcurrentAccess(t *testing.T) {
+	history := NewMemoryOrderHistory(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(size int64) {
+			defer wg.Done()
+			history.RecordOrder(size)
+		}(int64(i%5 + 1))
+		go func() {
+			defer wg.Done()
+			_ = history.GetOrders()
+		}()
+	}
+	wg.Wait()
+
+	total := int64(0)
+	for _, order := range history.GetOrders() {
+		total += order.Weight
+	}
+	assert.Equal(t, int64(50), total)
+}
@@ -74,3 +74,123 @@ func TestMemoryPackSizeRepository_ConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMemoryPackSizeRepository_Stock(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250, 500})
+	assert.NotNil(t, repo.GetStock())
+	assert.Empty(t, repo.GetStock())
+
+	stock := []domain.PackStock{{Size: 250, Available: 3}, {Size: 500, Available: 0}}
+	require.NoError(t, repo.UpdateStock(stock))
+	stock[0].Available = 99
+
+	got := repo.GetStock()
+	assert.Equal(t, []domain.PackStock{{Size: 250, Available: 3}, {Size: 500, Available: 0}}, got)
+
+	got[1].Available = 99
+	assert.Equal(t, 0, repo.GetStock()[1].Available)
+}
+
+func TestMemoryPackSizeRepository_Costs(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250, 500})
+	assert.NotNil(t, repo.GetCosts())
+	assert.Empty(t, repo.GetCosts())
+
+	costs := []domain.PackCost{{Size: 250, Cost: 120}, {Size: 500, Cost: 200}}
+	require.NoError(t, repo.UpdateCosts(costs))
+	costs[0].Cost = 1
+
+	got := repo.GetCosts()
+	assert.Equal(t, []domain.PackCost{{Size: 250, Cost: 120}, {Size: 500, Cost: 200}}, got)
+
+	got[1].Cost = 1
+	assert.Equal(t, int64(200), repo.GetCosts()[1].Cost)
+}
+
+func TestMemoryPackSizeRepository_Constraints(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250, 500})
+	assert.NotNil(t, repo.GetConstraints())
+	assert.Empty(t, repo.GetConstraints())
+
+	constraints := []domain.PackConstraint{{Size: 250, Min: 1}, {Size: 500, Min: 2, OrdersOver: 1000}}
+	require.NoError(t, repo.UpdateConstraints(constraints))
+	constraints[0].Min = 99
+
+	got := repo.GetConstraints()
+	assert.Equal(t, []domain.PackConstraint{{Size: 250, Min: 1}, {Size: 500, Min: 2, OrdersOver: 1000}}, got)
+
+	got[1].Min = 99
+	assert.Equal(t, 2, repo.GetConstraints()[1].Min)
+}
+
+func TestMemoryPackSizeRepository_Dimensions(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250})
+	assert.NotNil(t, repo.GetDimensions())
+	assert.Empty(t, repo.GetDimensions())
+
+	dimensions := []domain.PackDimensions{{Size: 250, Weight: 300, Length: 10, Width: 10, Height: 5}}
+	require.NoError(t, repo.UpdateDimensions(dimensions))
+	dimensions[0].Weight = 1
+
+	got := repo.GetDimensions()
+	assert.Equal(t, []domain.PackDimensions{{Size: 250, Weight: 300, Length: 10, Width: 10, Height: 5}}, got)
+
+	got[0].Weight = 1
+	assert.Equal(t, int64(300), repo.GetDimensions()[0].Weight)
+}
+
+func TestMemoryPackSizeRepository_Hierarchies(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250})
+	assert.NotNil(t, repo.GetHierarchies())
+	assert.Empty(t, repo.GetHierarchies())
+
+	hierarchies := []domain.PackHierarchy{{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 12}}}}
+	require.NoError(t, repo.UpdateHierarchies(hierarchies))
+	hierarchies[0].Levels[0].Contains = 99
+
+	got := repo.GetHierarchies()
+	assert.Equal(t, []domain.PackHierarchy{{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 12}}}}, got)
+
+	got[0].Levels[0].Contains = 99
+	assert.Equal(t, int64(12), repo.GetHierarchies()[0].Levels[0].Contains)
+}
+
+func TestMemoryPackSizeRepository_Pricing(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250})
+	assert.Equal(t, domain.Pricing{Prices: []domain.PackPrice{}}, repo.GetPricing())
+
+	pricing := domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+		{Size: 250, UnitPrice: 1500, DiscountTiers: []domain.DiscountTier{{MinPacks: 10, Percent: 50_000}}},
+	}}
+	require.NoError(t, repo.UpdatePricing(pricing))
+	pricing.Prices[0].DiscountTiers[0].Percent = 0
+
+	got := repo.GetPricing()
+	assert.Equal(t, domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+		{Size: 250, UnitPrice: 1500, DiscountTiers: []domain.DiscountTier{{MinPacks: 10, Percent: 50_000}}},
+	}}, got)
+
+	got.Prices[0].DiscountTiers[0].Percent = 0
+	assert.Equal(t, domain.Decimal(50_000), repo.GetPricing().Prices[0].DiscountTiers[0].Percent)
+}
+
+func TestMemoryPackSizeRepository_PackSetVersion(t *testing.T) {
+	repo := NewMemoryPackSizeRepository([]domain.PackSize{250})
+	v := repo.PackSetVersion()
+
+	require.NoError(t, repo.UpdatePackSizes([]domain.PackSize{250, 500}))
+	assert.Greater(t, repo.PackSetVersion(), v)
+	v = repo.PackSetVersion()
+
+	// Settings calculations read move it on too; the rest do not.
+	require.NoError(t, repo.UpdateStock([]domain.PackStock{{Size: 250, Available: 1}}))
+	require.NoError(t, repo.UpdateCosts([]domain.PackCost{{Size: 250, Cost: 1}}))
+	require.NoError(t, repo.UpdateConstraints(nil))
+	assert.Equal(t, v+3, repo.PackSetVersion())
+	v = repo.PackSetVersion()
+
+	require.NoError(t, repo.UpdateDimensions(nil))
+	require.NoError(t, repo.UpdateHierarchies(nil))
+	require.NoError(t, repo.UpdatePricing(domain.Pricing{}))
+	assert.Equal(t, v, repo.PackSetVersion())
+}
@@ -6,8 +6,15 @@ import (
 )
 
 type MemoryPackSizeRepository struct {
-	mu        sync.RWMutex
-	packSizes []domain.PackSize
+	mu          sync.RWMutex
+	packSizes   []domain.PackSize
+	stock       []domain.PackStock
+	costs       []domain.PackCost
+	constraints []domain.PackConstraint
+	dimensions  []domain.PackDimensions
+	hierarchies []domain.PackHierarchy
+	pricing     domain.Pricing
+	version     uint64
 }
 
 func NewMemoryPackSizeRepository(packSizes []domain.PackSize) domain.PackSizeRepository {
@@ -31,5 +38,139 @@ func (r *MemoryPackSizeRepository) UpdatePackSizes(sizes []domain.PackSize) erro
 
 	r.packSizes = make([]domain.PackSize, len(sizes))
 	copy(r.packSizes, sizes)
+	r.version++
 	return nil
 }
+
+func (r *MemoryPackSizeRepository) GetStock() []domain.PackStock {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cp := make([]domain.PackStock, len(r.stock))
+	copy(cp, r.stock)
+	return cp
+}
+
+func (r *MemoryPackSizeRepository) UpdateStock(stock []domain.PackStock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stock = make([]domain.PackStock, len(stock))
+	copy(r.stock, stock)
+	r.version++
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) GetCosts() []domain.PackCost {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cp := make([]domain.PackCost, len(r.costs))
+	copy(cp, r.costs)
+	return cp
+}
+
+func (r *MemoryPackSizeRepository) UpdateCosts(costs []domain.PackCost) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.costs = make([]domain.PackCost, len(costs))
+	copy(r.costs, costs)
+	r.version++
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) GetConstraints() []domain.PackConstraint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cp := make([]domain.PackConstraint, len(r.constraints))
+	copy(cp, r.constraints)
+	return cp
+}
+
+func (r *MemoryPackSizeRepository) UpdateConstraints(constraints []domain.PackConstraint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.constraints = make([]domain.PackConstraint, len(constraints))
+	copy(r.constraints, constraints)
+	r.version++
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) GetDimensions() []domain.PackDimensions {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cp := make([]domain.PackDimensions, len(r.dimensions))
+	copy(cp, r.dimensions)
+	return cp
+}
+
+func (r *MemoryPackSizeRepository) UpdateDimensions(dimensions []domain.PackDimensions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dimensions = make([]domain.PackDimensions, len(dimensions))
+	copy(r.dimensions, dimensions)
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) GetHierarchies() []domain.PackHierarchy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return copyHierarchies(r.hierarchies)
+}
+
+func (r *MemoryPackSizeRepository) UpdateHierarchies(hierarchies []domain.PackHierarchy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hierarchies = copyHierarchies(hierarchies)
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) GetPricing() domain.Pricing {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return copyPricing(r.pricing)
+}
+
+func (r *MemoryPackSizeRepository) UpdatePricing(pricing domain.Pricing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pricing = copyPricing(pricing)
+	return nil
+}
+
+func (r *MemoryPackSizeRepository) PackSetVersion() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.version
+}
+
+// copyHierarchies copies the levels too, so callers never share them.
+func copyHierarchies(hierarchies []domain.PackHierarchy) []domain.PackHierarchy {
+	cp := make([]domain.PackHierarchy, len(hierarchies))
+	for i, h := range hierarchies {
+		cp[i] = domain.PackHierarchy{Size: h.Size, Levels: make([]domain.PackagingLevel, len(h.Levels))}
+		copy(cp[i].Levels, h.Levels)
+	}
+	return cp
+}
+
+// copyPricing copies the discount tiers too, so callers never share them.
+func copyPricing(pricing domain.Pricing) domain.Pricing {
+	cp := domain.Pricing{Currency: pricing.Currency, Prices: make([]domain.PackPrice, len(pricing.Prices))}
+	for i, p := range pricing.Prices {
+		cp.Prices[i] = p
+		cp.Prices[i].DiscountTiers = make([]domain.DiscountTier, len(p.DiscountTiers))
+		copy(cp.Prices[i].DiscountTiers, p.DiscountTiers)
+	}
+	return cp
+}
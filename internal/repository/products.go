@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sort"
+	"sync"
+)
+
+type MemoryProductRepository struct {
+	mu       sync.RWMutex
+	products map[string][]domain.PackSize
+}
+
+func NewMemoryProductRepository() domain.ProductRepository {
+	return &MemoryProductRepository{products: make(map[string][]domain.PackSize)}
+}
+
+func (r *MemoryProductRepository) GetProducts() []domain.Product {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := make([]domain.Product, 0, len(r.products))
+	for id, sizes := range r.products {
+		products = append(products, domain.Product{ID: id, PackSizes: copySizes(sizes)})
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+	return products
+}
+
+func (r *MemoryProductRepository) GetProduct(id string) (domain.Product, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sizes, ok := r.products[id]
+	if !ok {
+		return domain.Product{}, false
+	}
+	return domain.Product{ID: id, PackSizes: copySizes(sizes)}, true
+}
+
+func (r *MemoryProductRepository) SaveProduct(product domain.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.products[product.ID] = copySizes(product.PackSizes)
+	return nil
+}
+
+func (r *MemoryProductRepository) DeleteProduct(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[id]; !ok {
+		return domain.ErrProductNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
+
+func copySizes(sizes []domain.PackSize) []domain.PackSize {
+	cp := make([]domain.PackSize, len(sizes))
+	copy(cp, sizes)
+	return cp
+}
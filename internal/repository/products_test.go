@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryProductRepository_SaveAndGet(t *testing.T) {
+	repo := NewMemoryProductRepository()
+	assert.NotNil(t, repo.GetProducts())
+	assert.Empty(t, repo.GetProducts())
+
+	sizes := []domain.PackSize{250, 500}
+	require.NoError(t, repo.SaveProduct(domain.Product{ID: "widget", PackSizes: sizes}))
+	require.NoError(t, repo.SaveProduct(domain.Product{ID: "bolt", PackSizes: []domain.PackSize{100}}))
+	sizes[0] = 9999
+
+	product, ok := repo.GetProduct("widget")
+	require.True(t, ok)
+	assert.Equal(t, domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 500}}, product)
+
+	product.PackSizes[0] = 9999
+	again, _ := repo.GetProduct("widget")
+	assert.Equal(t, domain.PackSize(250), again.PackSizes[0])
+
+	_, ok = repo.GetProduct("nut")
+	assert.False(t, ok)
+
+	assert.Equal(t, []domain.Product{
+		{ID: "bolt", PackSizes: []domain.PackSize{100}},
+		{ID: "widget", PackSizes: []domain.PackSize{250, 500}},
+	}, repo.GetProducts())
+}
+
+func TestMemoryProductRepository_Delete(t *testing.T) {
+	repo := NewMemoryProductRepository()
+	require.NoError(t, repo.SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250}}))
+
+	require.NoError(t, repo.DeleteProduct("widget"))
+	_, ok := repo.GetProduct("widget")
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, repo.DeleteProduct("widget"), domain.ErrProductNotFound)
+}
+
+func TestMemoryProductRepository_ConcurrentAccess(t *testing.T) {
+	repo := NewMemoryProductRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = repo.GetProducts()
+		}()
+		go func(v int) {
+			defer wg.Done()
+			_ = repo.SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{domain.PackSize(v + 1)}})
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sort"
+	"sync"
+)
+
+type MemoryWarehouseRepository struct {
+	mu         sync.RWMutex
+	warehouses map[string]domain.Warehouse
+}
+
+func NewMemoryWarehouseRepository() domain.WarehouseRepository {
+	return &MemoryWarehouseRepository{warehouses: make(map[string]domain.Warehouse)}
+}
+
+func (r *MemoryWarehouseRepository) GetWarehouses() []domain.Warehouse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	warehouses := make([]domain.Warehouse, 0, len(r.warehouses))
+	for _, w := range r.warehouses {
+		warehouses = append(warehouses, copyWarehouse(w))
+	}
+	sort.Slice(warehouses, func(i, j int) bool { return warehouses[i].ID < warehouses[j].ID })
+	return warehouses
+}
+
+func (r *MemoryWarehouseRepository) GetWarehouse(id string) (domain.Warehouse, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, ok := r.warehouses[id]
+	if !ok {
+		return domain.Warehouse{}, false
+	}
+	return copyWarehouse(w), true
+}
+
+func (r *MemoryWarehouseRepository) SaveWarehouse(warehouse domain.Warehouse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.warehouses[warehouse.ID] = copyWarehouse(warehouse)
+	return nil
+}
+
+func (r *MemoryWarehouseRepository) DeleteWarehouse(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.warehouses[id]; !ok {
+		return domain.ErrWarehouseNotFound
+	}
+	delete(r.warehouses, id)
+	return nil
+}
+
+func copyWarehouse(w domain.Warehouse) domain.Warehouse {
+	stock := make([]domain.PackStock, len(w.Stock))
+	copy(stock, w.Stock)
+	return domain.Warehouse{ID: w.ID, PackSizes: copySizes(w.PackSizes), Stock: stock}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"calculate_product_packs/internal/domain"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryWarehouseRepository_SaveAndGet(t *testing.T) {
+	repo := NewMemoryWarehouseRepository()
+	assert.NotNil(t, repo.GetWarehouses())
+	assert.Empty(t, repo.GetWarehouses())
+
+	north := domain.Warehouse{
+		ID:        "north",
+		PackSizes: []domain.PackSize{250, 500},
+		Stock:     []domain.PackStock{{Size: 250, Available: 4}},
+	}
+	require.NoError(t, repo.SaveWarehouse(north))
+	require.NoError(t, repo.SaveWarehouse(domain.Warehouse{ID: "east", PackSizes: []domain.PackSize{100}}))
+	north.PackSizes[0] = 9999
+	north.Stock[0].Available = 0
+
+	w, ok := repo.GetWarehouse("north")
+	require.True(t, ok)
+	assert.Equal(t, domain.Warehouse{
+		ID:        "north",
+		PackSizes: []domain.PackSize{250, 500},
+		Stock:     []domain.PackStock{{Size: 250, Available: 4}},
+	}, w)
+
+	w.Stock[0].Available = 0
+	again, _ := repo.GetWarehouse("north")
+	assert.Equal(t, 4, again.Stock[0].Available)
+
+	_, ok = repo.GetWarehouse("south")
+	assert.False(t, ok)
+
+	assert.Equal(t, []domain.Warehouse{
+		{ID: "east", PackSizes: []domain.PackSize{100}, Stock: []domain.PackStock{}},
+		{ID: "north", PackSizes: []domain.PackSize{250, 500}, Stock: []domain.PackStock{{Size: 250, Available: 4}}},
+	}, repo.GetWarehouses())
+}
+
+func TestMemoryWarehouseRepository_Delete(t *testing.T) {
+	repo := NewMemoryWarehouseRepository()
+	require.NoError(t, repo.SaveWarehouse(domain.Warehouse{ID: "north", PackSizes: []domain.PackSize{250}}))
+
+	require.NoError(t, repo.DeleteWarehouse("north"))
+	_, ok := repo.GetWarehouse("north")
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, repo.DeleteWarehouse("north"), domain.ErrWarehouseNotFound)
+}
+
+func TestMemoryWarehouseRepository_ConcurrentAccess(t *testing.T) {
+	repo := NewMemoryWarehouseRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = repo.GetWarehouses()
+		}()
+		go func(v int) {
+			defer wg.Done()
+			_ = repo.SaveWarehouse(domain.Warehouse{ID: "north", PackSizes: []domain.PackSize{domain.PackSize(v + 1)}})
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bufio"
+	"calculate_product_packs/internal/domain"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// batchDeadline replaces the server's read and write timeouts for a batch,
+// which streams for as long as its orders take.
+const batchDeadline = 5 * time.Minute
+
+var errInvalidBatchOrder = errors.New("invalid order size")
+
+// batchLine is one line of a batch response: the packs of an order, or its
+// error with the status a single calculation would have answered with.
+type batchLine struct {
+	Index     int                 `json:"index"`
+	OrderSize int64               `json:"orderSize"`
+	Packs     []domain.PackResult `json:"packs,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	Status    int                 `json:"status,omitempty"`
+}
+
+// CalculateBatch packs many orders in one request. The body is a JSON array
+// of order sizes or, with Content-Type application/x-ndjson, one order size
+// per line. The response streams one NDJSON line per order, in input order,
+// as results become available. An error that stops the batch after the first
+// line is reported as a last line with only an error.
+func (h *PackCalculatorHandler) CalculateBatch(w http.ResponseWriter, r *http.Request) {
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Results are written while the body is still being read. Writers that
+	// cannot do either, like test recorders, are left as they are.
+	_ = rc.EnableFullDuplex()
+	_ = rc.SetReadDeadline(time.Now().Add(batchDeadline))
+	_ = rc.SetWriteDeadline(time.Now().Add(batchDeadline))
+
+	orders, err := batchOrders(r)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	started := false
+	err = h.packCalculator.ExecuteBatch(r.Context(), orders, opts, func(res domain.BatchResult) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		line := batchLine{Index: res.Index, OrderSize: res.OrderSize, Packs: res.Packs}
+		if res.Err != nil {
+			line.Error, line.Status = res.Err.Error(), calculationStatus(res.Err)
+			if errors.Is(res.Err, errInvalidBatchOrder) {
+				line.Status = http.StatusBadRequest
+			}
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	if err == nil {
+		if !started {
+			// An empty batch.
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+		}
+		return
+	}
+
+	if !started {
+		writeCalculationError(w, err)
+		return
+	}
+	if err := enc.Encode(map[string]string{"error": err.Error()}); err != nil {
+		slog.Error("failed to write batch error", "error", err)
+	}
+}
+
+// batchOrders reads the order sizes of a batch body lazily, so that orders
+// can be packed while the rest is still arriving. A body that is neither an
+// NDJSON stream nor a JSON array fails at once; a value that is not an order
+// size yields errInvalidBatchOrder in its place. Malformed JSON in an array
+// ends it, as nothing after it can be read.
+func batchOrders(r *http.Request) (iter.Seq2[int64, error], error) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/x-ndjson" {
+		return func(yield func(int64, error) bool) {
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				text := strings.TrimSpace(scanner.Text())
+				if text == "" {
+					continue
+				}
+				var orderSize int64
+				if err := json.Unmarshal([]byte(text), &orderSize); err != nil {
+					if !yield(0, errInvalidBatchOrder) {
+						return
+					}
+					continue
+				}
+				if !yield(orderSize, nil) {
+					return
+				}
+			}
+			if scanner.Err() != nil {
+				yield(0, errInvalidBatchOrder)
+			}
+		}, nil
+	}
+
+	dec := json.NewDecoder(r.Body)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errInvalidBatchOrder
+	}
+	return func(yield func(int64, error) bool) {
+		for dec.More() {
+			var orderSize int64
+			if err := dec.Decode(&orderSize); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if !yield(0, errInvalidBatchOrder) || !errors.As(err, &typeErr) {
+					return
+				}
+				continue
+			}
+			if !yield(orderSize, nil) {
+				return
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/transport/http/mocks"
+	"context"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// packEach answers ExecuteBatch with one pack of the order size per order,
+// and ErrOrderSizePositive for orders below 1.
+func packEach(_ context.Context, orders iter.Seq2[int64, error], _ domain.CalculateOptions, emit func(domain.BatchResult) error) error {
+	i := 0
+	for orderSize, err := range orders {
+		r := domain.BatchResult{Index: i, OrderSize: orderSize, Err: err}
+		if err == nil && orderSize < 1 {
+			r.Err = domain.ErrOrderSizePositive
+		} else if err == nil {
+			r.Packs = []domain.PackResult{{Size: domain.PackSize(orderSize), Count: 1}}
+		}
+		if err := emit(r); err != nil {
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
+func TestPackCalculatorHandler_CalculateBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		contentType    string
+		body           string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "json array",
+			body: `[250, 0, 500]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).DoAndReturn(packEach)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"index":0,"orderSize":250,"packs":[{"size":250,"count":1}]}` + "\n" +
+				`{"index":1,"orderSize":0,"error":"order size must be greater than 0","status":400}` + "\n" +
+				`{"index":2,"orderSize":500,"packs":[{"size":500,"count":1}]}` + "\n",
+		},
+		{
+			name:  "options apply to every order",
+			query: "policy=fewest-packs",
+			body:  `[250]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{Policy: "fewest-packs"}, gomock.Any()).DoAndReturn(packEach)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"index":0,"orderSize":250,"packs":[{"size":250,"count":1}]}` + "\n",
+		},
+		{
+			name: "invalid value in array",
+			body: `[250, "x", 500]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).DoAndReturn(packEach)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"index":0,"orderSize":250,"packs":[{"size":250,"count":1}]}` + "\n" +
+				`{"index":1,"orderSize":0,"error":"invalid order size","status":400}` + "\n" +
+				`{"index":2,"orderSize":500,"packs":[{"size":500,"count":1}]}` + "\n",
+		},
+		{
+			name:        "ndjson",
+			contentType: "application/x-ndjson",
+			body:        "250\n\nx\n500\n",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).DoAndReturn(packEach)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"index":0,"orderSize":250,"packs":[{"size":250,"count":1}]}` + "\n" +
+				`{"index":1,"orderSize":0,"error":"invalid order size","status":400}` + "\n" +
+				`{"index":2,"orderSize":500,"packs":[{"size":500,"count":1}]}` + "\n",
+		},
+		{
+			name: "empty batch",
+			body: `[]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).DoAndReturn(packEach)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:           "not an array",
+			body:           `{"orders":[250]}`,
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name:           "invalid option",
+			query:          "exact=maybe",
+			body:           `[250]`,
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid exact flag\n",
+		},
+		{
+			name: "no pack sizes",
+			body: `[250]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).Return(domain.ErrNoPackSizes)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no pack sizes available\n",
+		},
+		{
+			name: "error after the first result",
+			body: `[250, 500]`,
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any(), domain.CalculateOptions{}, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ iter.Seq2[int64, error], _ domain.CalculateOptions, emit func(domain.BatchResult) error) error {
+						if err := emit(domain.BatchResult{Index: 0, OrderSize: 250, Packs: []domain.PackResult{{Size: 250, Count: 1}}}); err != nil {
+							return err
+						}
+						return domain.ErrTooManyBatchOrders
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"index":0,"orderSize":250,"packs":[{"size":250,"count":1}]}` + "\n" +
+				`{"error":"too many orders in batch"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("POST", "/api/calculate/batch?"+tt.query, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+			handler.CalculateBatch(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -2,22 +2,45 @@ package http
 
 import (
 	"calculate_product_packs/internal/domain"
+	"context"
 	"encoding/json"
 	"errors"
+	"iter"
 	"log/slog"
 	"net/http"
 	"strconv"
 )
 
+const defaultAlternatives = 3
+
 //go:generate mockgen -destination=mocks/mock_pack_calculator.go -package=mocks calculate_product_packs/internal/transport/http PackCalculator
 type PackCalculator interface {
-	Execute(orderSize int) ([]domain.PackResult, error)
+	Execute(ctx context.Context, orderSize int64, opts domain.CalculateOptions) ([]domain.PackResult, error)
+	Explain(ctx context.Context, orderSize int64, opts domain.CalculateOptions) (*domain.Calculation, error)
+	Alternatives(ctx context.Context, orderSize int64, k int) ([]domain.Alternative, error)
+	PlanShipments(ctx context.Context, orderSize int64, opts domain.CalculateOptions, limits domain.ParcelLimits) (*domain.ShipmentPlan, error)
+	PlanPackaging(ctx context.Context, orderSize int64, opts domain.CalculateOptions) (*domain.PackagingPlan, error)
+	ExecuteBatch(ctx context.Context, orders iter.Seq2[int64, error], opts domain.CalculateOptions, emit func(domain.BatchResult) error) error
+	Stats() domain.CalculationStats
 }
 
 //go:generate mockgen -destination=mocks/mock_pack_sizer.go -package=mocks calculate_product_packs/internal/transport/http PackSizer
 type PackSizer interface {
 	UpdatePackSizes(sizes []domain.PackSize) error
 	GetPackSizes() []domain.PackSize
+	Analyze(ctx context.Context) (*domain.PackSetAnalysis, error)
+	UpdateStock(stock []domain.PackStock) error
+	GetStock() []domain.PackStock
+	UpdateCosts(costs []domain.PackCost) error
+	GetCosts() []domain.PackCost
+	UpdateConstraints(constraints []domain.PackConstraint) error
+	GetConstraints() []domain.PackConstraint
+	UpdateDimensions(dimensions []domain.PackDimensions) error
+	GetDimensions() []domain.PackDimensions
+	UpdateHierarchies(hierarchies []domain.PackHierarchy) error
+	GetHierarchies() []domain.PackHierarchy
+	UpdatePricing(pricing domain.Pricing) error
+	GetPricing() domain.Pricing
 }
 
 type PackCalculatorHandler struct {
@@ -36,28 +59,136 @@ func NewPackCalculatorHandler(
 }
 
 func (h *PackCalculatorHandler) CalculatePacks(w http.ResponseWriter, r *http.Request) {
-	orderSize, err := strconv.Atoi(r.URL.Query().Get("orderSize"))
+	orderSize, err := orderSizeParam(r)
 	if err != nil {
 		http.Error(w, "Invalid order size", http.StatusBadRequest)
 		return
 	}
 
-	result, err := h.packCalculator.Execute(orderSize)
+	explain, err := boolParam(r, "explain")
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrOrderSizePositive):
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		case errors.Is(err, domain.ErrNoPackSizes):
-			http.Error(w, err.Error(), http.StatusNotFound)
-		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Invalid explain flag", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	if explain {
+		calculation, err := h.packCalculator.Explain(r.Context(), orderSize, opts)
+		if err != nil {
+			writeCalculationError(w, err)
+			return
 		}
+		writeJSON(w, calculation)
+		return
+	}
+
+	result, err := h.packCalculator.Execute(r.Context(), orderSize, opts)
+	if err != nil {
+		writeCalculationError(w, err)
 		return
 	}
 
 	writeJSON(w, result)
 }
 
+func (h *PackCalculatorHandler) CalculateAlternatives(w http.ResponseWriter, r *http.Request) {
+	orderSize, err := orderSizeParam(r)
+	if err != nil {
+		http.Error(w, "Invalid order size", http.StatusBadRequest)
+		return
+	}
+
+	k := defaultAlternatives
+	if v := r.URL.Query().Get("k"); v != "" {
+		if k, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Invalid alternative count", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.packCalculator.Alternatives(r.Context(), orderSize, k)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// PlanShipments calculates packs like CalculatePacks and splits them into
+// parcels within maxWeight (grams) and maxVolume (cubic centimetres).
+func (h *PackCalculatorHandler) PlanShipments(w http.ResponseWriter, r *http.Request) {
+	orderSize, err := orderSizeParam(r)
+	if err != nil {
+		http.Error(w, "Invalid order size", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	var limits domain.ParcelLimits
+	maxWeight, err := int64Param(r, "maxWeight")
+	if err != nil {
+		http.Error(w, "Invalid max weight", http.StatusBadRequest)
+		return
+	}
+	if maxWeight != nil {
+		limits.MaxWeight = *maxWeight
+	}
+	maxVolume, err := int64Param(r, "maxVolume")
+	if err != nil {
+		http.Error(w, "Invalid max volume", http.StatusBadRequest)
+		return
+	}
+	if maxVolume != nil {
+		limits.MaxVolume = *maxVolume
+	}
+
+	plan, err := h.packCalculator.PlanShipments(r.Context(), orderSize, opts, limits)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, plan)
+}
+
+// PlanPackaging calculates packs like CalculatePacks and consolidates them
+// into their packaging hierarchies.
+func (h *PackCalculatorHandler) PlanPackaging(w http.ResponseWriter, r *http.Request) {
+	orderSize, err := orderSizeParam(r)
+	if err != nil {
+		http.Error(w, "Invalid order size", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.packCalculator.PlanPackaging(r.Context(), orderSize, opts)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, plan)
+}
+
+// GetStats reports on how calculations were served, such as the hits and
+// misses of the result cache.
+func (h *PackCalculatorHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packCalculator.Stats())
+}
+
 func (h *PackCalculatorHandler) UpdatePackSizes(w http.ResponseWriter, r *http.Request) {
 	var sizes []domain.PackSize
 	if err := json.NewDecoder(r.Body).Decode(&sizes); err != nil {
@@ -88,8 +219,309 @@ func (h *PackCalculatorHandler) GetPackSizes(w http.ResponseWriter, r *http.Requ
 	writeJSON(w, sizes)
 }
 
+func (h *PackCalculatorHandler) AnalyzePackSizes(w http.ResponseWriter, r *http.Request) {
+	analysis, err := h.packSizesUseCase.Analyze(r.Context())
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, analysis)
+}
+
+func (h *PackCalculatorHandler) UpdateStock(w http.ResponseWriter, r *http.Request) {
+	var stock []domain.PackStock
+	if err := json.NewDecoder(r.Body).Decode(&stock); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdateStock(stock); err != nil {
+		if errors.Is(err, domain.ErrInvalidStock) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update stock", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Stock updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetStock())
+}
+
+func (h *PackCalculatorHandler) UpdateCosts(w http.ResponseWriter, r *http.Request) {
+	var costs []domain.PackCost
+	if err := json.NewDecoder(r.Body).Decode(&costs); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdateCosts(costs); err != nil {
+		if errors.Is(err, domain.ErrInvalidPackCost) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update pack costs", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Pack costs updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetCosts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetCosts())
+}
+
+func (h *PackCalculatorHandler) UpdateDimensions(w http.ResponseWriter, r *http.Request) {
+	var dimensions []domain.PackDimensions
+	if err := json.NewDecoder(r.Body).Decode(&dimensions); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdateDimensions(dimensions); err != nil {
+		if errors.Is(err, domain.ErrInvalidPackDimensions) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update pack dimensions", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Pack dimensions updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetDimensions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetDimensions())
+}
+
+func (h *PackCalculatorHandler) UpdateHierarchies(w http.ResponseWriter, r *http.Request) {
+	var hierarchies []domain.PackHierarchy
+	if err := json.NewDecoder(r.Body).Decode(&hierarchies); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdateHierarchies(hierarchies); err != nil {
+		if errors.Is(err, domain.ErrInvalidPackaging) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update packaging", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Packaging updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetHierarchies(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetHierarchies())
+}
+
+func (h *PackCalculatorHandler) UpdatePricing(w http.ResponseWriter, r *http.Request) {
+	var pricing domain.Pricing
+	if err := json.NewDecoder(r.Body).Decode(&pricing); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdatePricing(pricing); err != nil {
+		if errors.Is(err, domain.ErrInvalidPricing) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update pricing", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Pricing updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetPricing(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetPricing())
+}
+
+func (h *PackCalculatorHandler) UpdateConstraints(w http.ResponseWriter, r *http.Request) {
+	var constraints []domain.PackConstraint
+	if err := json.NewDecoder(r.Body).Decode(&constraints); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.packSizesUseCase.UpdateConstraints(constraints); err != nil {
+		if errors.Is(err, domain.ErrInvalidPackConstraint) || errors.Is(err, domain.ErrUnsatisfiablePackConstraint) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to update pack constraints", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Pack constraints updated successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *PackCalculatorHandler) GetConstraints(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.packSizesUseCase.GetConstraints())
+}
+
+// calculateOptions parses the query parameters shared by the calculate
+// endpoints. On an invalid one it writes the 400 response and returns false.
+func calculateOptions(w http.ResponseWriter, r *http.Request) (domain.CalculateOptions, bool) {
+	var err error
+	opts := domain.CalculateOptions{Policy: r.URL.Query().Get("policy")}
+	if opts.RespectStock, err = boolParam(r, "respectStock"); err != nil {
+		http.Error(w, "Invalid respectStock flag", http.StatusBadRequest)
+		return opts, false
+	}
+	if opts.ExactOnly, err = boolParam(r, "exact"); err != nil {
+		http.Error(w, "Invalid exact flag", http.StatusBadRequest)
+		return opts, false
+	}
+	if opts.MaxOvershoot, err = int64Param(r, "maxOvershoot"); err != nil {
+		http.Error(w, "Invalid max overshoot", http.StatusBadRequest)
+		return opts, false
+	}
+	if opts.MaxOvershootPercent, err = intParam(r, "maxOvershootPercent"); err != nil {
+		http.Error(w, "Invalid max overshoot percent", http.StatusBadRequest)
+		return opts, false
+	}
+	return opts, true
+}
+
+// boolParam parses an optional boolean query parameter; absent means false.
+func boolParam(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+// intParam parses an optional integer query parameter; absent means nil.
+func intParam(r *http.Request, name string) (*int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// int64Param parses an optional 64-bit integer query parameter; absent means
+// nil.
+func int64Param(r *http.Request, name string) (*int64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// orderSizeParam parses the required orderSize query parameter, which may
+// use the full int64 range.
+func orderSizeParam(r *http.Request) (int64, error) {
+	return strconv.ParseInt(r.URL.Query().Get("orderSize"), 10, 64)
+}
+
+func writeCalculationError(w http.ResponseWriter, err error) {
+	// A stock shortfall comes with the packs that could still be sent, an
+	// overshoot with the nearest totals that can.
+	var stockErr *domain.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		writeJSONStatus(w, http.StatusConflict, stockErr)
+		return
+	}
+	var overshootErr *domain.OvershootError
+	if errors.As(err, &overshootErr) {
+		writeJSONStatus(w, http.StatusUnprocessableEntity, overshootErr)
+		return
+	}
+
+	http.Error(w, err.Error(), calculationStatus(err))
+}
+
+// calculationStatus is the HTTP status for an error from a calculation.
+func calculationStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrOrderSizePositive),
+		errors.Is(err, domain.ErrInvalidAlternativeCount),
+		errors.Is(err, domain.ErrInvalidReportRange),
+		errors.Is(err, domain.ErrInvalidRecommendation),
+		errors.Is(err, domain.ErrInvalidWorkload),
+		errors.Is(err, domain.ErrEmptyWorkload),
+		errors.Is(err, domain.ErrEmptyPackSizes),
+		errors.Is(err, domain.ErrInvalidPackSize),
+		errors.Is(err, domain.ErrTooManyPackSizes),
+		errors.Is(err, domain.ErrInvalidPolicy),
+		errors.Is(err, domain.ErrInvalidOvershoot),
+		errors.Is(err, domain.ErrInvalidParcelLimits),
+		errors.Is(err, domain.ErrEmptyOrder),
+		errors.Is(err, domain.ErrTooManyOrderLines),
+		errors.Is(err, domain.ErrInvalidWarehousePreference):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNoPackSizes),
+		errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrWarehouseNotFound),
+		errors.Is(err, domain.ErrNoWarehouses):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTooManyBatchOrders):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrOrderTooLarge),
+		errors.Is(err, domain.ErrOvershootLimitExceeded),
+		errors.Is(err, domain.ErrTotalOverflow),
+		errors.Is(err, domain.ErrPackConstraintsUnmet),
+		errors.Is(err, domain.ErrMissingPackDimensions),
+		errors.Is(err, domain.ErrPackExceedsParcel),
+		errors.Is(err, domain.ErrMissingPackCosts),
+		errors.Is(err, domain.ErrMissingPackPrices),
+		errors.Is(err, domain.ErrQuoteTooLarge):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrComputeBudgetExceeded):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrCalculationCanceled):
+		return http.StatusRequestTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func writeJSON(w http.ResponseWriter, v any) {
+	writeJSONStatus(w, http.StatusOK, v)
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(v); err != nil {
 		slog.Error("failed to encode JSON response", "error", err)
 	}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"calculate_product_packs/internal/domain"
 	"calculate_product_packs/internal/transport/http/mocks"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -26,7 +29,7 @@ func TestPackCalculatorHandler_CalculatePacks(t *testing.T) {
 			name:      "Valid order size",
 			orderSize: "500",
 			mockSetup: func(m *mocks.MockPackCalculator) {
-				m.EXPECT().Execute(500).Return([]domain.PackResult{{Size: 500, Count: 1}}, nil)
+				m.EXPECT().Execute(gomock.Any(), int64(500), domain.CalculateOptions{}).Return([]domain.PackResult{{Size: 500, Count: 1}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   `[{"size":500,"count":1}]` + "\n",
@@ -42,7 +45,7 @@ func TestPackCalculatorHandler_CalculatePacks(t *testing.T) {
 			name:      "Order size must be greater than zero",
 			orderSize: "0",
 			mockSetup: func(m *mocks.MockPackCalculator) {
-				m.EXPECT().Execute(0).Return(nil, domain.ErrOrderSizePositive)
+				m.EXPECT().Execute(gomock.Any(), int64(0), domain.CalculateOptions{}).Return(nil, domain.ErrOrderSizePositive)
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "order size must be greater than 0\n",
@@ -51,20 +54,74 @@ func TestPackCalculatorHandler_CalculatePacks(t *testing.T) {
 			name:      "No pack sizes available",
 			orderSize: "100",
 			mockSetup: func(m *mocks.MockPackCalculator) {
-				m.EXPECT().Execute(100).Return(nil, domain.ErrNoPackSizes)
+				m.EXPECT().Execute(gomock.Any(), int64(100), domain.CalculateOptions{}).Return(nil, domain.ErrNoPackSizes)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "no pack sizes available\n",
 		},
+		{
+			name:      "Compute budget exceeded",
+			orderSize: "1000",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(1000), domain.CalculateOptions{}).Return(nil, domain.ErrComputeBudgetExceeded)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "calculation exceeded its compute budget\n",
+		},
+		{
+			name:      "Calculation canceled",
+			orderSize: "1000",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(1000), domain.CalculateOptions{}).Return(nil, fmt.Errorf("%w: %w", domain.ErrCalculationCanceled, context.Canceled))
+			},
+			expectedStatus: http.StatusRequestTimeout,
+			expectedBody:   "calculation canceled: context canceled\n",
+		},
 		{
 			name:      "Internal server error",
 			orderSize: "1000",
 			mockSetup: func(m *mocks.MockPackCalculator) {
-				m.EXPECT().Execute(1000).Return(nil, errors.New("unexpected error"))
+				m.EXPECT().Execute(gomock.Any(), int64(1000), domain.CalculateOptions{}).Return(nil, errors.New("unexpected error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "unexpected error\n",
 		},
+		{
+			name:      "Order size at the int64 limit",
+			orderSize: "9223372036854775807",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(math.MaxInt64), domain.CalculateOptions{}).
+					Return([]domain.PackResult{{Size: 1, Count: math.MaxInt64}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"size":1,"count":9223372036854775807}]` + "\n",
+		},
+		{
+			name:           "Order size beyond the int64 limit",
+			orderSize:      "9223372036854775808",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid order size\n",
+		},
+		{
+			name:      "Total beyond the int64 limit",
+			orderSize: "9223372036854775807",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(math.MaxInt64), domain.CalculateOptions{}).Return(nil, domain.ErrTotalOverflow)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "order total exceeds the 64-bit range\n",
+		},
+		{
+			name:      "Pack constraints unmet",
+			orderSize: "1000",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(1000), domain.CalculateOptions{}).
+					Return(nil, fmt.Errorf("%w: at most 750 items fit", domain.ErrPackConstraintsUnmet))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "order cannot meet the pack constraints: at most 750 items fit\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,7 +153,7 @@ func TestPackCalculatorHandler_CalculatePacks_JSONResponse(t *testing.T) {
 		{Size: 500, Count: 1},
 		{Size: 250, Count: 1},
 	}
-	mockCalculator.EXPECT().Execute(750).Return(expectedResult, nil)
+	mockCalculator.EXPECT().Execute(gomock.Any(), int64(750), domain.CalculateOptions{}).Return(expectedResult, nil)
 
 	handler := NewPackCalculatorHandler(mockCalculator, nil)
 
@@ -113,6 +170,157 @@ func TestPackCalculatorHandler_CalculatePacks_JSONResponse(t *testing.T) {
 	assert.Equal(t, expectedResult, result)
 }
 
+func TestPackCalculatorHandler_CalculatePacks_Explain(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "explain",
+			query: "orderSize=251&explain=true",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Explain(gomock.Any(), int64(251), domain.CalculateOptions{}).Return(&domain.Calculation{
+					Packs:          []domain.PackResult{{Size: 500, Count: 1}},
+					TotalItems:     500,
+					Overshoot:      249,
+					PackCount:      1,
+					Policy:         "fewest-items",
+					DecidedBy:      domain.RuleFewestPacks,
+					PackSetVersion: 3,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"packs":[{"size":500,"count":1}],"totalItems":500,"overshoot":249,"packCount":1,` +
+				`"policy":"fewest-items","decidedBy":"fewest-packs","largeOrderShortcut":false,"packSetVersion":3}` + "\n",
+		},
+		{
+			name:  "explain disabled",
+			query: "orderSize=251&explain=false",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(251), domain.CalculateOptions{}).Return([]domain.PackResult{{Size: 500, Count: 1}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"size":500,"count":1}]` + "\n",
+		},
+		{
+			name:           "invalid flag",
+			query:          "orderSize=251&explain=maybe",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid explain flag\n",
+		},
+		{
+			name:  "error",
+			query: "orderSize=251&explain=1",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Explain(gomock.Any(), int64(251), domain.CalculateOptions{}).Return(nil, domain.ErrNoPackSizes)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no pack sizes available\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("GET", "/api/calculate?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.CalculatePacks(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_CalculateAlternatives(t *testing.T) {
+	alternatives := []domain.Alternative{
+		{Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, PackCount: 1},
+		{Packs: []domain.PackResult{{Size: 250, Count: 2}}, TotalItems: 500, PackCount: 2},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "explicit count",
+			query: "orderSize=251&k=2",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Alternatives(gomock.Any(), int64(251), 2).Return(alternatives, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"packs":[{"size":500,"count":1}],"totalItems":500,"packCount":1},` +
+				`{"packs":[{"size":250,"count":2}],"totalItems":500,"packCount":2}]` + "\n",
+		},
+		{
+			name:  "default count",
+			query: "orderSize=251",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Alternatives(gomock.Any(), int64(251), defaultAlternatives).Return(alternatives, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid order size",
+			query:          "orderSize=abc",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid order size\n",
+		},
+		{
+			name:           "invalid count",
+			query:          "orderSize=251&k=many",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid alternative count\n",
+		},
+		{
+			name:  "count out of range",
+			query: "orderSize=251&k=50",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Alternatives(gomock.Any(), int64(251), 50).Return(nil, domain.ErrInvalidAlternativeCount)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "alternative count must be between 1 and 10\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("GET", "/api/calculate/alternatives?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.CalculateAlternatives(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestPackCalculatorHandler_UpdatePackSizes(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -199,3 +407,658 @@ func TestPackCalculatorHandler_GetPackSizes(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []domain.PackSize{250, 500, 1000}, sizes)
 }
+
+func TestPackCalculatorHandler_AnalyzePackSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().Analyze(gomock.Any()).Return(&domain.PackSetAnalysis{
+		PackSizes:       []domain.PackSize{250, 500},
+		GCD:             250,
+		FrobeniusNumber: -1,
+		RedundantSizes:  []domain.PackSize{500},
+	}, nil)
+	mockSizer.EXPECT().Analyze(gomock.Any()).Return(nil, domain.ErrNoPackSizes)
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	rr := httptest.NewRecorder()
+	handler.AnalyzePackSizes(rr, httptest.NewRequest("GET", "/api/pack-sizes/analysis", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"packSizes":[250,500],"gcd":250,"frobeniusNumber":-1,"redundantSizes":[500]}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.AnalyzePackSizes(rr, httptest.NewRequest("GET", "/api/pack-sizes/analysis", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPackCalculatorHandler_CalculatePacks_Options(t *testing.T) {
+	stockOpts := domain.CalculateOptions{RespectStock: true}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "stock respected",
+			query: "orderSize=501&respectStock=true",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(501), stockOpts).Return([]domain.PackResult{{Size: 250, Count: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"size":250,"count":3}]` + "\n",
+		},
+		{
+			name:           "invalid flag",
+			query:          "orderSize=501&respectStock=maybe",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid respectStock flag\n",
+		},
+		{
+			name:  "insufficient stock reports what can be sent",
+			query: "orderSize=1000&respectStock=true",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(1000), stockOpts).Return(nil, &domain.InsufficientStockError{
+					OrderSize: 1000,
+					Available: 750,
+					Shortfall: 250,
+					Packs:     []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+				})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"orderSize":1000,"available":750,"shortfall":250,` +
+				`"packs":[{"size":500,"count":1},{"size":250,"count":1}]}` + "\n",
+		},
+		{
+			name:  "lowest-cost policy with overshoot cap",
+			query: "orderSize=501&policy=lowest-cost&maxOvershoot=300",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(501), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ int64, opts domain.CalculateOptions) ([]domain.PackResult, error) {
+						assert.Equal(t, "lowest-cost", opts.Policy)
+						assert.Equal(t, int64(300), *opts.MaxOvershoot)
+						return []domain.PackResult{{Size: 250, Count: 3}}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"size":250,"count":3}]` + "\n",
+		},
+		{
+			name:  "exact only refused",
+			query: "orderSize=251&exact=true",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(251), domain.CalculateOptions{ExactOnly: true}).Return(nil, &domain.OvershootError{
+					OrderSize:    251,
+					NearestBelow: 250,
+					NearestAbove: 500,
+				})
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"orderSize":251,"maxOvershoot":0,"nearestBelow":250,"nearestAbove":500}` + "\n",
+		},
+		{
+			name:           "invalid exact flag",
+			query:          "orderSize=251&exact=sure",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid exact flag\n",
+		},
+		{
+			name:           "invalid max overshoot percent",
+			query:          "orderSize=251&maxOvershootPercent=ten",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid max overshoot percent\n",
+		},
+		{
+			name:           "invalid max overshoot",
+			query:          "orderSize=501&maxOvershoot=lots",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid max overshoot\n",
+		},
+		{
+			name:  "invalid policy",
+			query: "orderSize=501&policy=cheapest",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(501), domain.CalculateOptions{Policy: "cheapest"}).Return(nil, domain.ErrInvalidPolicy)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid policy\n",
+		},
+		{
+			name:  "missing pack costs",
+			query: "orderSize=501&policy=lowest-cost",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(501), domain.CalculateOptions{Policy: "lowest-cost"}).Return(nil, domain.ErrMissingPackCosts)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "some pack sizes have no cost\n",
+		},
+		{
+			name:  "order too large",
+			query: "orderSize=100000000&respectStock=true",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().Execute(gomock.Any(), int64(100000000), stockOpts).Return(nil, domain.ErrOrderTooLarge)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "order too large for a constrained calculation\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("GET", "/api/calculate?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.CalculatePacks(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_UpdateStock(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSizer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid update",
+			body: `[{"size":250,"available":10},{"size":500,"available":0}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateStock([]domain.PackStock{{Size: 250, Available: 10}, {Size: 500, Available: 0}}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Stock updated successfully",
+		},
+		{
+			name:           "invalid JSON",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockPackSizer) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name: "invalid entry",
+			body: `[{"size":250,"available":-1}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateStock([]domain.PackStock{{Size: 250, Available: -1}}).Return(domain.ErrInvalidStock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid stock entry\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSizer := mocks.NewMockPackSizer(ctrl)
+			tt.mockSetup(mockSizer)
+
+			handler := NewPackCalculatorHandler(nil, mockSizer)
+
+			req := httptest.NewRequest("PUT", "/api/stock", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.UpdateStock(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_GetStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().GetStock().Return([]domain.PackStock{{Size: 250, Available: 4}})
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	req := httptest.NewRequest("GET", "/api/stock", nil)
+	rr := httptest.NewRecorder()
+	handler.GetStock(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"size":250,"available":4}]`+"\n", rr.Body.String())
+}
+
+func TestPackCalculatorHandler_UpdateCosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSizer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid update",
+			body: `[{"size":250,"cost":40},{"size":500,"cost":100}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateCosts([]domain.PackCost{{Size: 250, Cost: 40}, {Size: 500, Cost: 100}}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Pack costs updated successfully",
+		},
+		{
+			name:           "invalid JSON",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockPackSizer) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name: "invalid entry",
+			body: `[{"size":250,"cost":0}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateCosts([]domain.PackCost{{Size: 250, Cost: 0}}).Return(domain.ErrInvalidPackCost)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid pack cost\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSizer := mocks.NewMockPackSizer(ctrl)
+			tt.mockSetup(mockSizer)
+
+			handler := NewPackCalculatorHandler(nil, mockSizer)
+
+			req := httptest.NewRequest("PUT", "/api/pack-costs", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.UpdateCosts(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_GetCosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().GetCosts().Return([]domain.PackCost{{Size: 250, Cost: 40}})
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	req := httptest.NewRequest("GET", "/api/pack-costs", nil)
+	rr := httptest.NewRecorder()
+	handler.GetCosts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"size":250,"cost":40}]`+"\n", rr.Body.String())
+}
+
+func TestPackCalculatorHandler_UpdateConstraints(t *testing.T) {
+	three := 3
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSizer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid update",
+			body: `[{"size":250,"max":3},{"size":5000,"min":1,"ordersOver":20000}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateConstraints([]domain.PackConstraint{
+					{Size: 250, Max: &three},
+					{Size: 5000, Min: 1, OrdersOver: 20000},
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Pack constraints updated successfully",
+		},
+		{
+			name:           "invalid JSON",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockPackSizer) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name: "unsatisfiable",
+			body: `[{"size":250,"min":4,"max":3}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateConstraints(gomock.Any()).
+					Return(fmt.Errorf("%w: orders over 0 items need at least 4 and at most 3 packs of 250", domain.ErrUnsatisfiablePackConstraint))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "pack constraints cannot be satisfied: orders over 0 items need at least 4 and at most 3 packs of 250\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSizer := mocks.NewMockPackSizer(ctrl)
+			tt.mockSetup(mockSizer)
+
+			handler := NewPackCalculatorHandler(nil, mockSizer)
+
+			req := httptest.NewRequest("PUT", "/api/pack-constraints", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.UpdateConstraints(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_PlanShipments(t *testing.T) {
+	plan := &domain.ShipmentPlan{
+		Packs:       []domain.PackResult{{Size: 250, Count: 3}},
+		ParcelCount: 2,
+		Optimal:     true,
+		Parcels: []domain.Parcel{
+			{Count: 1, Packs: []domain.PackResult{{Size: 250, Count: 2}}, Weight: 500, Volume: 2000},
+			{Count: 1, Packs: []domain.PackResult{{Size: 250, Count: 1}}, Weight: 250, Volume: 1000},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "plan",
+			query: "orderSize=600&maxWeight=500&policy=fewest-packs",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().PlanShipments(gomock.Any(), int64(600), domain.CalculateOptions{Policy: "fewest-packs"}, domain.ParcelLimits{MaxWeight: 500}).Return(plan, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"packs":[{"size":250,"count":3}],"parcelCount":2,"optimal":true,"parcels":[` +
+				`{"count":1,"packs":[{"size":250,"count":2}],"weight":500,"volume":2000},` +
+				`{"count":1,"packs":[{"size":250,"count":1}],"weight":250,"volume":1000}]}` + "\n",
+		},
+		{
+			name:           "invalid max volume",
+			query:          "orderSize=600&maxVolume=big",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid max volume\n",
+		},
+		{
+			name:  "missing limits",
+			query: "orderSize=600",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().PlanShipments(gomock.Any(), int64(600), domain.CalculateOptions{}, domain.ParcelLimits{}).Return(nil, domain.ErrInvalidParcelLimits)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidParcelLimits.Error() + "\n",
+		},
+		{
+			name:  "pack too large for a parcel",
+			query: "orderSize=600&maxWeight=100",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().PlanShipments(gomock.Any(), int64(600), domain.CalculateOptions{}, domain.ParcelLimits{MaxWeight: 100}).Return(nil, domain.ErrPackExceedsParcel)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   "pack does not fit in a parcel\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("GET", "/api/calculate/shipments?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.PlanShipments(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_UpdateDimensions(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSizer)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid update",
+			body: `[{"size":250,"weight":300,"length":10,"width":10,"height":5}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateDimensions([]domain.PackDimensions{{Size: 250, Weight: 300, Length: 10, Width: 10, Height: 5}}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Pack dimensions updated successfully",
+		},
+		{
+			name: "invalid entry",
+			body: `[{"size":250}]`,
+			mockSetup: func(m *mocks.MockPackSizer) {
+				m.EXPECT().UpdateDimensions([]domain.PackDimensions{{Size: 250}}).Return(domain.ErrInvalidPackDimensions)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid pack dimensions\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSizer := mocks.NewMockPackSizer(ctrl)
+			tt.mockSetup(mockSizer)
+
+			handler := NewPackCalculatorHandler(nil, mockSizer)
+
+			req := httptest.NewRequest("PUT", "/api/pack-dimensions", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.UpdateDimensions(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_PlanPackaging(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockPackCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "plan",
+			query: "orderSize=3000",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().PlanPackaging(gomock.Any(), int64(3000), domain.CalculateOptions{}).Return(&domain.PackagingPlan{
+					Packs: []domain.PackResult{{Size: 250, Count: 12}},
+					Consolidation: []domain.Consolidation{
+						{Size: 250, Packs: 12, Units: []domain.PackagingUnit{{Name: "case", Count: 1}}, LoosePacks: 0},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"packs":[{"size":250,"count":12}],"consolidation":[` +
+				`{"size":250,"packs":12,"units":[{"name":"case","count":1}],"loosePacks":0}]}` + "\n",
+		},
+		{
+			name:           "invalid order size",
+			query:          "orderSize=abc",
+			mockSetup:      func(m *mocks.MockPackCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid order size\n",
+		},
+		{
+			name:  "no pack sizes",
+			query: "orderSize=3000",
+			mockSetup: func(m *mocks.MockPackCalculator) {
+				m.EXPECT().PlanPackaging(gomock.Any(), int64(3000), domain.CalculateOptions{}).Return(nil, domain.ErrNoPackSizes)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no pack sizes available\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalculator := mocks.NewMockPackCalculator(ctrl)
+			tt.mockSetup(mockCalculator)
+
+			handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+			req := httptest.NewRequest("GET", "/api/calculate/packaging?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.PlanPackaging(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestPackCalculatorHandler_UpdateHierarchies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().UpdateHierarchies([]domain.PackHierarchy{
+		{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 12}, {Name: "pallet", Contains: 40}}},
+	}).Return(nil)
+	mockSizer.EXPECT().UpdateHierarchies([]domain.PackHierarchy{{Size: 250}}).Return(domain.ErrInvalidPackaging)
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	req := httptest.NewRequest("PUT", "/api/packaging",
+		bytes.NewBufferString(`[{"size":250,"levels":[{"name":"case","contains":12},{"name":"pallet","contains":40}]}]`))
+	rr := httptest.NewRecorder()
+	handler.UpdateHierarchies(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Packaging updated successfully", rr.Body.String())
+
+	req = httptest.NewRequest("PUT", "/api/packaging", bytes.NewBufferString(`[{"size":250}]`))
+	rr = httptest.NewRecorder()
+	handler.UpdateHierarchies(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "invalid packaging hierarchy\n", rr.Body.String())
+}
+
+func TestPackCalculatorHandler_UpdatePricing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().UpdatePricing(domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+		{Size: 250, UnitPrice: 125, Surcharge: 5000, DiscountTiers: []domain.DiscountTier{{MinPacks: 10, Percent: 50_000}}},
+	}}).Return(nil)
+	mockSizer.EXPECT().UpdatePricing(domain.Pricing{Currency: "euro"}).Return(domain.ErrInvalidPricing)
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	req := httptest.NewRequest("PUT", "/api/pricing",
+		bytes.NewBufferString(`{"currency":"EUR","prices":[{"size":250,"unitPrice":"0.0125","surcharge":0.5,"discountTiers":[{"minPacks":10,"percent":"5"}]}]}`))
+	rr := httptest.NewRecorder()
+	handler.UpdatePricing(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "Pricing updated successfully", rr.Body.String())
+
+	req = httptest.NewRequest("PUT", "/api/pricing", bytes.NewBufferString(`{"currency":"euro"}`))
+	rr = httptest.NewRecorder()
+	handler.UpdatePricing(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "invalid pricing\n", rr.Body.String())
+
+	// Prices with more than four decimals are not silently rounded.
+	req = httptest.NewRequest("PUT", "/api/pricing",
+		bytes.NewBufferString(`{"currency":"EUR","prices":[{"size":250,"unitPrice":"0.00125"}]}`))
+	rr = httptest.NewRecorder()
+	handler.UpdatePricing(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "Invalid request body\n", rr.Body.String())
+}
+
+func TestPackCalculatorHandler_GetPricing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSizer := mocks.NewMockPackSizer(ctrl)
+	mockSizer.EXPECT().GetPricing().Return(domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{{Size: 250, UnitPrice: 125}}})
+
+	handler := NewPackCalculatorHandler(nil, mockSizer)
+
+	req := httptest.NewRequest("GET", "/api/pricing", nil)
+	rr := httptest.NewRecorder()
+	handler.GetPricing(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"currency":"EUR","prices":[{"size":250,"unitPrice":"0.0125","surcharge":"0.00","discountTiers":null}]}`+"\n", rr.Body.String())
+}
+
+func TestPackCalculatorHandler_GetStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCalculator := mocks.NewMockPackCalculator(ctrl)
+	mockCalculator.EXPECT().Stats().Return(domain.CalculationStats{
+		PackSetVersion: 3,
+		ResultCache:    domain.ResultCacheStats{Enabled: true, Capacity: 1000, Size: 2, Hits: 3, Misses: 1, HitRate: 0.75},
+		Coalescing:     domain.CoalescingStats{Enabled: true, Calls: 4, Deduplicated: 2},
+		Shadow:         domain.ShadowStats{Enabled: true, Percent: 5, Checked: 3, Disagreements: 1},
+	})
+
+	handler := NewPackCalculatorHandler(mockCalculator, nil)
+
+	req := httptest.NewRequest("GET", "/api/calculate/stats", nil)
+	rr := httptest.NewRecorder()
+	handler.GetStats(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"packSetVersion":3,"resultCache":{"enabled":true,"capacity":1000,"size":2,"hits":3,"misses":1,"evictions":0,"hitRate":0.75},"coalescing":{"enabled":true,"calls":4,"deduplicated":2,"inFlight":0},"shadow":{"enabled":true,"percent":5,"checked":3,"disagreements":1,"inconclusive":0,"skipped":0}}`+"\n", rr.Body.String())
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: OrderCalculator)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderCalculator is a mock of OrderCalculator interface.
+type MockOrderCalculator struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderCalculatorMockRecorder
+}
+
+// MockOrderCalculatorMockRecorder is the mock recorder for MockOrderCalculator.
+type MockOrderCalculatorMockRecorder struct {
+	mock *MockOrderCalculator
+}
+
+// NewMockOrderCalculator creates a new mock instance.
+func NewMockOrderCalculator(ctrl *gomock.Controller) *MockOrderCalculator {
+	mock := &MockOrderCalculator{ctrl: ctrl}
+	mock.recorder = &MockOrderCalculatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderCalculator) EXPECT() *MockOrderCalculatorMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockOrderCalculator) Execute(arg0 context.Context, arg1 []domain.OrderLine, arg2 domain.CalculateOptions) (*domain.OrderResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.OrderResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockOrderCalculatorMockRecorder) Execute(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderCalculator)(nil).Execute), arg0, arg1, arg2)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: OrderSplitter)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderSplitter is a mock of OrderSplitter interface.
+type MockOrderSplitter struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderSplitterMockRecorder
+}
+
+// MockOrderSplitterMockRecorder is the mock recorder for MockOrderSplitter.
+type MockOrderSplitterMockRecorder struct {
+	mock *MockOrderSplitter
+}
+
+// NewMockOrderSplitter creates a new mock instance.
+func NewMockOrderSplitter(ctrl *gomock.Controller) *MockOrderSplitter {
+	mock := &MockOrderSplitter{ctrl: ctrl}
+	mock.recorder = &MockOrderSplitterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderSplitter) EXPECT() *MockOrderSplitterMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockOrderSplitter) Execute(arg0 context.Context, arg1 int64, arg2 []domain.WarehousePreference, arg3 domain.CalculateOptions) (*domain.WarehouseSplit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.WarehouseSplit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockOrderSplitterMockRecorder) Execute(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderSplitter)(nil).Execute), arg0, arg1, arg2, arg3)
+}
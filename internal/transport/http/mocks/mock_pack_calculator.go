@@ -5,6 +5,8 @@ package mocks
 
 import (
 	domain "calculate_product_packs/internal/domain"
+	context "context"
+	iter "iter"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -33,17 +35,105 @@ func (m *MockPackCalculator) EXPECT() *MockPackCalculatorMockRecorder {
 	return m.recorder
 }
 
+// Alternatives mocks base method.
+func (m *MockPackCalculator) Alternatives(arg0 context.Context, arg1 int64, arg2 int) ([]domain.Alternative, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Alternatives", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]domain.Alternative)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Alternatives indicates an expected call of Alternatives.
+func (mr *MockPackCalculatorMockRecorder) Alternatives(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Alternatives", reflect.TypeOf((*MockPackCalculator)(nil).Alternatives), arg0, arg1, arg2)
+}
+
 // Execute mocks base method.
-func (m *MockPackCalculator) Execute(arg0 int) ([]domain.PackResult, error) {
+func (m *MockPackCalculator) Execute(arg0 context.Context, arg1 int64, arg2 domain.CalculateOptions) ([]domain.PackResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Execute", arg0)
+	ret := m.ctrl.Call(m, "Execute", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]domain.PackResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Execute indicates an expected call of Execute.
-func (mr *MockPackCalculatorMockRecorder) Execute(arg0 any) *gomock.Call {
+func (mr *MockPackCalculatorMockRecorder) Execute(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockPackCalculator)(nil).Execute), arg0, arg1, arg2)
+}
+
+// ExecuteBatch mocks base method.
+func (m *MockPackCalculator) ExecuteBatch(arg0 context.Context, arg1 iter.Seq2[int64, error], arg2 domain.CalculateOptions, arg3 func(domain.BatchResult) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteBatch", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteBatch indicates an expected call of ExecuteBatch.
+func (mr *MockPackCalculatorMockRecorder) ExecuteBatch(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBatch", reflect.TypeOf((*MockPackCalculator)(nil).ExecuteBatch), arg0, arg1, arg2, arg3)
+}
+
+// Explain mocks base method.
+func (m *MockPackCalculator) Explain(arg0 context.Context, arg1 int64, arg2 domain.CalculateOptions) (*domain.Calculation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Explain", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Calculation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Explain indicates an expected call of Explain.
+func (mr *MockPackCalculatorMockRecorder) Explain(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Explain", reflect.TypeOf((*MockPackCalculator)(nil).Explain), arg0, arg1, arg2)
+}
+
+// PlanPackaging mocks base method.
+func (m *MockPackCalculator) PlanPackaging(arg0 context.Context, arg1 int64, arg2 domain.CalculateOptions) (*domain.PackagingPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanPackaging", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PackagingPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanPackaging indicates an expected call of PlanPackaging.
+func (mr *MockPackCalculatorMockRecorder) PlanPackaging(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanPackaging", reflect.TypeOf((*MockPackCalculator)(nil).PlanPackaging), arg0, arg1, arg2)
+}
+
+// PlanShipments mocks base method.
+func (m *MockPackCalculator) PlanShipments(arg0 context.Context, arg1 int64, arg2 domain.CalculateOptions, arg3 domain.ParcelLimits) (*domain.ShipmentPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanShipments", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.ShipmentPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanShipments indicates an expected call of PlanShipments.
+func (mr *MockPackCalculatorMockRecorder) PlanShipments(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanShipments", reflect.TypeOf((*MockPackCalculator)(nil).PlanShipments), arg0, arg1, arg2, arg3)
+}
+
+// Stats mocks base method.
+func (m *MockPackCalculator) Stats() domain.CalculationStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(domain.CalculationStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockPackCalculatorMockRecorder) Stats() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockPackCalculator)(nil).Execute), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockPackCalculator)(nil).Stats))
 }
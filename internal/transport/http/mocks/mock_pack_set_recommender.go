@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: PackSetRecommender)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPackSetRecommender is a mock of PackSetRecommender interface.
+type MockPackSetRecommender struct {
+	ctrl     *gomock.Controller
+	recorder *MockPackSetRecommenderMockRecorder
+}
+
+// MockPackSetRecommenderMockRecorder is the mock recorder for MockPackSetRecommender.
+type MockPackSetRecommenderMockRecorder struct {
+	mock *MockPackSetRecommender
+}
+
+// NewMockPackSetRecommender creates a new mock instance.
+func NewMockPackSetRecommender(ctrl *gomock.Controller) *MockPackSetRecommender {
+	mock := &MockPackSetRecommender{ctrl: ctrl}
+	mock.recorder = &MockPackSetRecommenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPackSetRecommender) EXPECT() *MockPackSetRecommenderMockRecorder {
+	return m.recorder
+}
+
+// Recommend mocks base method.
+func (m *MockPackSetRecommender) Recommend(arg0 context.Context, arg1 domain.RecommendationRequest) (*domain.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recommend", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recommend indicates an expected call of Recommend.
+func (mr *MockPackSetRecommenderMockRecorder) Recommend(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommend", reflect.TypeOf((*MockPackSetRecommender)(nil).Recommend), arg0, arg1)
+}
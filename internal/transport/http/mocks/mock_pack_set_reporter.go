@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: PackSetReporter)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPackSetReporter is a mock of PackSetReporter interface.
+type MockPackSetReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockPackSetReporterMockRecorder
+}
+
+// MockPackSetReporterMockRecorder is the mock recorder for MockPackSetReporter.
+type MockPackSetReporterMockRecorder struct {
+	mock *MockPackSetReporter
+}
+
+// NewMockPackSetReporter creates a new mock instance.
+func NewMockPackSetReporter(ctrl *gomock.Controller) *MockPackSetReporter {
+	mock := &MockPackSetReporter{ctrl: ctrl}
+	mock.recorder = &MockPackSetReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPackSetReporter) EXPECT() *MockPackSetReporterMockRecorder {
+	return m.recorder
+}
+
+// BreakpointTable mocks base method.
+func (m *MockPackSetReporter) BreakpointTable(arg0 context.Context, arg1 int64) (*domain.BreakpointTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BreakpointTable", arg0, arg1)
+	ret0, _ := ret[0].(*domain.BreakpointTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BreakpointTable indicates an expected call of BreakpointTable.
+func (mr *MockPackSetReporterMockRecorder) BreakpointTable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakpointTable", reflect.TypeOf((*MockPackSetReporter)(nil).BreakpointTable), arg0, arg1)
+}
+
+// Report mocks base method.
+func (m *MockPackSetReporter) Report(arg0 context.Context, arg1 []domain.PackSize, arg2 int64) (*domain.PackSetReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.PackSetReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockPackSetReporterMockRecorder) Report(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockPackSetReporter)(nil).Report), arg0, arg1, arg2)
+}
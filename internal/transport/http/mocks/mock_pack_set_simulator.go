@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: PackSetSimulator)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPackSetSimulator is a mock of PackSetSimulator interface.
+type MockPackSetSimulator struct {
+	ctrl     *gomock.Controller
+	recorder *MockPackSetSimulatorMockRecorder
+}
+
+// MockPackSetSimulatorMockRecorder is the mock recorder for MockPackSetSimulator.
+type MockPackSetSimulatorMockRecorder struct {
+	mock *MockPackSetSimulator
+}
+
+// NewMockPackSetSimulator creates a new mock instance.
+func NewMockPackSetSimulator(ctrl *gomock.Controller) *MockPackSetSimulator {
+	mock := &MockPackSetSimulator{ctrl: ctrl}
+	mock.recorder = &MockPackSetSimulatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPackSetSimulator) EXPECT() *MockPackSetSimulatorMockRecorder {
+	return m.recorder
+}
+
+// Simulate mocks base method.
+func (m *MockPackSetSimulator) Simulate(arg0 context.Context, arg1 domain.SimulationRequest) (*domain.Simulation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Simulate", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Simulation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Simulate indicates an expected call of Simulate.
+func (mr *MockPackSetSimulatorMockRecorder) Simulate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Simulate", reflect.TypeOf((*MockPackSetSimulator)(nil).Simulate), arg0, arg1)
+}
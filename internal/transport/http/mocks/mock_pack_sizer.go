@@ -5,6 +5,7 @@ package mocks
 
 import (
 	domain "calculate_product_packs/internal/domain"
+	context "context"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -33,6 +34,77 @@ func (m *MockPackSizer) EXPECT() *MockPackSizerMockRecorder {
 	return m.recorder
 }
 
+// Analyze mocks base method.
+func (m *MockPackSizer) Analyze(arg0 context.Context) (*domain.PackSetAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Analyze", arg0)
+	ret0, _ := ret[0].(*domain.PackSetAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Analyze indicates an expected call of Analyze.
+func (mr *MockPackSizerMockRecorder) Analyze(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analyze", reflect.TypeOf((*MockPackSizer)(nil).Analyze), arg0)
+}
+
+// GetConstraints mocks base method.
+func (m *MockPackSizer) GetConstraints() []domain.PackConstraint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConstraints")
+	ret0, _ := ret[0].([]domain.PackConstraint)
+	return ret0
+}
+
+// GetConstraints indicates an expected call of GetConstraints.
+func (mr *MockPackSizerMockRecorder) GetConstraints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConstraints", reflect.TypeOf((*MockPackSizer)(nil).GetConstraints))
+}
+
+// GetCosts mocks base method.
+func (m *MockPackSizer) GetCosts() []domain.PackCost {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCosts")
+	ret0, _ := ret[0].([]domain.PackCost)
+	return ret0
+}
+
+// GetCosts indicates an expected call of GetCosts.
+func (mr *MockPackSizerMockRecorder) GetCosts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCosts", reflect.TypeOf((*MockPackSizer)(nil).GetCosts))
+}
+
+// GetDimensions mocks base method.
+func (m *MockPackSizer) GetDimensions() []domain.PackDimensions {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDimensions")
+	ret0, _ := ret[0].([]domain.PackDimensions)
+	return ret0
+}
+
+// GetDimensions indicates an expected call of GetDimensions.
+func (mr *MockPackSizerMockRecorder) GetDimensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDimensions", reflect.TypeOf((*MockPackSizer)(nil).GetDimensions))
+}
+
+// GetHierarchies mocks base method.
+func (m *MockPackSizer) GetHierarchies() []domain.PackHierarchy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHierarchies")
+	ret0, _ := ret[0].([]domain.PackHierarchy)
+	return ret0
+}
+
+// GetHierarchies indicates an expected call of GetHierarchies.
+func (mr *MockPackSizerMockRecorder) GetHierarchies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHierarchies", reflect.TypeOf((*MockPackSizer)(nil).GetHierarchies))
+}
+
 // GetPackSizes mocks base method.
 func (m *MockPackSizer) GetPackSizes() []domain.PackSize {
 	m.ctrl.T.Helper()
@@ -47,6 +119,90 @@ func (mr *MockPackSizerMockRecorder) GetPackSizes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackSizes", reflect.TypeOf((*MockPackSizer)(nil).GetPackSizes))
 }
 
+// GetPricing mocks base method.
+func (m *MockPackSizer) GetPricing() domain.Pricing {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPricing")
+	ret0, _ := ret[0].(domain.Pricing)
+	return ret0
+}
+
+// GetPricing indicates an expected call of GetPricing.
+func (mr *MockPackSizerMockRecorder) GetPricing() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPricing", reflect.TypeOf((*MockPackSizer)(nil).GetPricing))
+}
+
+// GetStock mocks base method.
+func (m *MockPackSizer) GetStock() []domain.PackStock {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock")
+	ret0, _ := ret[0].([]domain.PackStock)
+	return ret0
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockPackSizerMockRecorder) GetStock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockPackSizer)(nil).GetStock))
+}
+
+// UpdateConstraints mocks base method.
+func (m *MockPackSizer) UpdateConstraints(arg0 []domain.PackConstraint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConstraints", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConstraints indicates an expected call of UpdateConstraints.
+func (mr *MockPackSizerMockRecorder) UpdateConstraints(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConstraints", reflect.TypeOf((*MockPackSizer)(nil).UpdateConstraints), arg0)
+}
+
+// UpdateCosts mocks base method.
+func (m *MockPackSizer) UpdateCosts(arg0 []domain.PackCost) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCosts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCosts indicates an expected call of UpdateCosts.
+func (mr *MockPackSizerMockRecorder) UpdateCosts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCosts", reflect.TypeOf((*MockPackSizer)(nil).UpdateCosts), arg0)
+}
+
+// UpdateDimensions mocks base method.
+func (m *MockPackSizer) UpdateDimensions(arg0 []domain.PackDimensions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDimensions", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDimensions indicates an expected call of UpdateDimensions.
+func (mr *MockPackSizerMockRecorder) UpdateDimensions(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDimensions", reflect.TypeOf((*MockPackSizer)(nil).UpdateDimensions), arg0)
+}
+
+// UpdateHierarchies mocks base method.
+func (m *MockPackSizer) UpdateHierarchies(arg0 []domain.PackHierarchy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHierarchies", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHierarchies indicates an expected call of UpdateHierarchies.
+func (mr *MockPackSizerMockRecorder) UpdateHierarchies(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHierarchies", reflect.TypeOf((*MockPackSizer)(nil).UpdateHierarchies), arg0)
+}
+
 // UpdatePackSizes mocks base method.
 func (m *MockPackSizer) UpdatePackSizes(arg0 []domain.PackSize) error {
 	m.ctrl.T.Helper()
@@ -60,3 +216,31 @@ func (mr *MockPackSizerMockRecorder) UpdatePackSizes(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePackSizes", reflect.TypeOf((*MockPackSizer)(nil).UpdatePackSizes), arg0)
 }
+
+// UpdatePricing mocks base method.
+func (m *MockPackSizer) UpdatePricing(arg0 domain.Pricing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePricing", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePricing indicates an expected call of UpdatePricing.
+func (mr *MockPackSizerMockRecorder) UpdatePricing(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePricing", reflect.TypeOf((*MockPackSizer)(nil).UpdatePricing), arg0)
+}
+
+// UpdateStock mocks base method.
+func (m *MockPackSizer) UpdateStock(arg0 []domain.PackStock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockPackSizerMockRecorder) UpdateStock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockPackSizer)(nil).UpdateStock), arg0)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: Quoter)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuoter is a mock of Quoter interface.
+type MockQuoter struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoterMockRecorder
+}
+
+// MockQuoterMockRecorder is the mock recorder for MockQuoter.
+type MockQuoterMockRecorder struct {
+	mock *MockQuoter
+}
+
+// NewMockQuoter creates a new mock instance.
+func NewMockQuoter(ctrl *gomock.Controller) *MockQuoter {
+	mock := &MockQuoter{ctrl: ctrl}
+	mock.recorder = &MockQuoterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoter) EXPECT() *MockQuoterMockRecorder {
+	return m.recorder
+}
+
+// Quote mocks base method.
+func (m *MockQuoter) Quote(arg0 context.Context, arg1 int64, arg2 domain.CalculateOptions) (*domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockQuoterMockRecorder) Quote(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockQuoter)(nil).Quote), arg0, arg1, arg2)
+}
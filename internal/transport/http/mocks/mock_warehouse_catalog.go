@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calculate_product_packs/internal/transport/http (interfaces: WarehouseCatalog)
+
+package mocks
+
+import (
+	domain "calculate_product_packs/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWarehouseCatalog is a mock of WarehouseCatalog interface.
+type MockWarehouseCatalog struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarehouseCatalogMockRecorder
+}
+
+// MockWarehouseCatalogMockRecorder is the mock recorder for MockWarehouseCatalog.
+type MockWarehouseCatalogMockRecorder struct {
+	mock *MockWarehouseCatalog
+}
+
+// NewMockWarehouseCatalog creates a new mock instance.
+func NewMockWarehouseCatalog(ctrl *gomock.Controller) *MockWarehouseCatalog {
+	mock := &MockWarehouseCatalog{ctrl: ctrl}
+	mock.recorder = &MockWarehouseCatalogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarehouseCatalog) EXPECT() *MockWarehouseCatalogMockRecorder {
+	return m.recorder
+}
+
+// DeleteWarehouse mocks base method.
+func (m *MockWarehouseCatalog) DeleteWarehouse(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWarehouse indicates an expected call of DeleteWarehouse.
+func (mr *MockWarehouseCatalogMockRecorder) DeleteWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWarehouse", reflect.TypeOf((*MockWarehouseCatalog)(nil).DeleteWarehouse), arg0)
+}
+
+// GetWarehouses mocks base method.
+func (m *MockWarehouseCatalog) GetWarehouses() []domain.Warehouse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWarehouses")
+	ret0, _ := ret[0].([]domain.Warehouse)
+	return ret0
+}
+
+// GetWarehouses indicates an expected call of GetWarehouses.
+func (mr *MockWarehouseCatalogMockRecorder) GetWarehouses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWarehouses", reflect.TypeOf((*MockWarehouseCatalog)(nil).GetWarehouses))
+}
+
+// SaveWarehouse mocks base method.
+func (m *MockWarehouseCatalog) SaveWarehouse(arg0 domain.Warehouse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWarehouse", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWarehouse indicates an expected call of SaveWarehouse.
+func (mr *MockWarehouseCatalogMockRecorder) SaveWarehouse(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWarehouse", reflect.TypeOf((*MockWarehouseCatalog)(nil).SaveWarehouse), arg0)
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+//go:generate mockgen -destination=mocks/mock_order_calculator.go -package=mocks calculate_product_packs/internal/transport/http OrderCalculator
+type OrderCalculator interface {
+	Execute(ctx context.Context, lines []domain.OrderLine, opts domain.CalculateOptions) (*domain.OrderResult, error)
+}
+
+//go:generate mockgen -destination=mocks/mock_product_catalog.go -package=mocks calculate_product_packs/internal/transport/http ProductCatalog
+type ProductCatalog interface {
+	GetProducts() []domain.Product
+	SaveProduct(product domain.Product) error
+	DeleteProduct(id string) error
+}
+
+type OrderHandler struct {
+	orderCalculator OrderCalculator
+	productCatalog  ProductCatalog
+}
+
+func NewOrderHandler(orderCalculator OrderCalculator, productCatalog ProductCatalog) *OrderHandler {
+	return &OrderHandler{
+		orderCalculator: orderCalculator,
+		productCatalog:  productCatalog,
+	}
+}
+
+type orderRequest struct {
+	Lines []domain.OrderLine `json:"lines"`
+}
+
+// CalculateOrder packs a multi-line order. It takes the same query
+// parameters as CalculatePacks, applied to every line.
+func (h *OrderHandler) CalculateOrder(w http.ResponseWriter, r *http.Request) {
+	var req orderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.orderCalculator.Execute(r.Context(), req.Lines, opts)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+func (h *OrderHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.productCatalog.GetProducts())
+}
+
+// SaveProduct stores the pack sizes in the body under the product ID in the
+// path.
+func (h *OrderHandler) SaveProduct(w http.ResponseWriter, r *http.Request) {
+	var sizes []domain.PackSize
+	if err := json.NewDecoder(r.Body).Decode(&sizes); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.productCatalog.SaveProduct(domain.Product{ID: r.PathValue("id"), PackSizes: sizes}); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidProductID),
+			errors.Is(err, domain.ErrEmptyPackSizes),
+			errors.Is(err, domain.ErrInvalidPackSize),
+			errors.Is(err, domain.ErrTooManyPackSizes):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to save product", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Product saved successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *OrderHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	if err := h.productCatalog.DeleteProduct(r.PathValue("id")); err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete product", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,234 @@
+package http
+
+import (
+	"bytes"
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/transport/http/mocks"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOrderHandler_CalculateOrder(t *testing.T) {
+	lines := []domain.OrderLine{{ProductID: "widget", Quantity: 501}, {ProductID: "bolt", Quantity: 10}}
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		mockSetup      func(m *mocks.MockOrderCalculator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid order",
+			body: `{"lines":[{"productId":"widget","quantity":501},{"productId":"bolt","quantity":10}]}`,
+			mockSetup: func(m *mocks.MockOrderCalculator) {
+				m.EXPECT().Execute(gomock.Any(), lines, domain.CalculateOptions{}).Return(&domain.OrderResult{
+					Lines: []domain.LineResult{
+						{ProductID: "widget", Quantity: 501, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, Overshoot: 249, PackCount: 2},
+						{ProductID: "bolt", Quantity: 10, Packs: []domain.PackResult{{Size: 12, Count: 1}}, TotalItems: 12, Overshoot: 2, PackCount: 1},
+					},
+					TotalItems: 762,
+					Overshoot:  251,
+					PackCount:  3,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"lines":[` +
+				`{"productId":"widget","quantity":501,"packs":[{"size":500,"count":1},{"size":250,"count":1}],"totalItems":750,"overshoot":249,"packCount":2},` +
+				`{"productId":"bolt","quantity":10,"packs":[{"size":12,"count":1}],"totalItems":12,"overshoot":2,"packCount":1}` +
+				`],"totalItems":762,"overshoot":251,"packCount":3}` + "\n",
+		},
+		{
+			name:  "options apply to every line",
+			query: "?policy=fewest-packs&respectStock=true",
+			body:  `{"lines":[{"productId":"widget","quantity":501},{"productId":"bolt","quantity":10}]}`,
+			mockSetup: func(m *mocks.MockOrderCalculator) {
+				m.EXPECT().Execute(gomock.Any(), lines, domain.CalculateOptions{Policy: "fewest-packs", RespectStock: true}).Return(&domain.OrderResult{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"lines":null,"totalItems":0,"overshoot":0,"packCount":0}` + "\n",
+		},
+		{
+			name:           "invalid JSON",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockOrderCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name:           "invalid option",
+			query:          "?exact=maybe",
+			body:           `{"lines":[]}`,
+			mockSetup:      func(m *mocks.MockOrderCalculator) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid exact flag\n",
+		},
+		{
+			name: "empty order",
+			body: `{"lines":[]}`,
+			mockSetup: func(m *mocks.MockOrderCalculator) {
+				m.EXPECT().Execute(gomock.Any(), []domain.OrderLine{}, domain.CalculateOptions{}).Return(nil, domain.ErrEmptyOrder)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "order must have at least one line\n",
+		},
+		{
+			name: "unknown product",
+			body: `{"lines":[{"productId":"nut","quantity":1}]}`,
+			mockSetup: func(m *mocks.MockOrderCalculator) {
+				m.EXPECT().Execute(gomock.Any(), []domain.OrderLine{{ProductID: "nut", Quantity: 1}}, domain.CalculateOptions{}).
+					Return(nil, fmt.Errorf("line 1: %w", domain.ErrProductNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "line 1: product not found\n",
+		},
+		{
+			name: "invalid quantity",
+			body: `{"lines":[{"productId":"widget","quantity":0}]}`,
+			mockSetup: func(m *mocks.MockOrderCalculator) {
+				m.EXPECT().Execute(gomock.Any(), []domain.OrderLine{{ProductID: "widget", Quantity: 0}}, domain.CalculateOptions{}).
+					Return(nil, fmt.Errorf("line 1: %w", domain.ErrOrderSizePositive))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "line 1: order size must be greater than 0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCalc := mocks.NewMockOrderCalculator(ctrl)
+			tt.mockSetup(mockCalc)
+
+			handler := NewOrderHandler(mockCalc, nil)
+
+			req := httptest.NewRequest("POST", "/api/calculate/order"+tt.query, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.CalculateOrder(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestOrderHandler_SaveProduct(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		mockSetup      func(m *mocks.MockProductCatalog)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid product",
+			id:   "widget",
+			body: `[250, 500]`,
+			mockSetup: func(m *mocks.MockProductCatalog) {
+				m.EXPECT().SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 500}}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Product saved successfully",
+		},
+		{
+			name:           "invalid JSON",
+			id:             "widget",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockProductCatalog) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name: "invalid pack size",
+			id:   "widget",
+			body: `[0]`,
+			mockSetup: func(m *mocks.MockProductCatalog) {
+				m.EXPECT().SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{0}}).Return(domain.ErrInvalidPackSize)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid pack size\n",
+		},
+		{
+			name: "invalid product ID",
+			id:   "a b",
+			body: `[250]`,
+			mockSetup: func(m *mocks.MockProductCatalog) {
+				m.EXPECT().SaveProduct(domain.Product{ID: "a b", PackSizes: []domain.PackSize{250}}).Return(domain.ErrInvalidProductID)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidProductID.Error() + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCatalog := mocks.NewMockProductCatalog(ctrl)
+			tt.mockSetup(mockCatalog)
+
+			handler := NewOrderHandler(nil, mockCatalog)
+
+			req := httptest.NewRequest("PUT", "/api/products/x", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.SaveProduct(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestOrderHandler_DeleteProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCatalog := mocks.NewMockProductCatalog(ctrl)
+	mockCatalog.EXPECT().DeleteProduct("widget").Return(nil)
+	mockCatalog.EXPECT().DeleteProduct("nut").Return(domain.ErrProductNotFound)
+
+	handler := NewOrderHandler(nil, mockCatalog)
+
+	req := httptest.NewRequest("DELETE", "/api/products/widget", nil)
+	req.SetPathValue("id", "widget")
+	rr := httptest.NewRecorder()
+	handler.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/products/nut", nil)
+	req.SetPathValue("id", "nut")
+	rr = httptest.NewRecorder()
+	handler.DeleteProduct(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "product not found\n", rr.Body.String())
+}
+
+func TestOrderHandler_GetProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCatalog := mocks.NewMockProductCatalog(ctrl)
+	mockCatalog.EXPECT().GetProducts().Return([]domain.Product{{ID: "widget", PackSizes: []domain.PackSize{250, 500}}})
+
+	handler := NewOrderHandler(nil, mockCatalog)
+
+	req := httptest.NewRequest("GET", "/api/products", nil)
+	rr := httptest.NewRecorder()
+	handler.GetProducts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"id":"widget","packSizes":[250,500]}]`+"\n", rr.Body.String())
+}
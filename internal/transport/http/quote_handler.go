@@ -0,0 +1,43 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"net/http"
+)
+
+//go:generate mockgen -destination=mocks/mock_quoter.go -package=mocks calculate_product_packs/internal/transport/http Quoter
+type Quoter interface {
+	Quote(ctx context.Context, orderSize int64, opts domain.CalculateOptions) (*domain.Quote, error)
+}
+
+type QuoteHandler struct {
+	quoter Quoter
+}
+
+func NewQuoteHandler(quoter Quoter) *QuoteHandler {
+	return &QuoteHandler{quoter: quoter}
+}
+
+// Quote prices the packing of an order. It takes the same query parameters
+// as CalculatePacks.
+func (h *QuoteHandler) Quote(w http.ResponseWriter, r *http.Request) {
+	orderSize, err := orderSizeParam(r)
+	if err != nil {
+		http.Error(w, "Invalid order size", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	quote, err := h.quoter.Quote(r.Context(), orderSize, opts)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, quote)
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/transport/http/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestQuoteHandler_Quote(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(m *mocks.MockQuoter)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "valid quote",
+			query: "?orderSize=501",
+			mockSetup: func(m *mocks.MockQuoter) {
+				m.EXPECT().Quote(gomock.Any(), int64(501), domain.CalculateOptions{}).Return(&domain.Quote{
+					OrderSize: 501,
+					Currency:  "EUR",
+					Lines: []domain.QuoteLine{
+						{Size: 250, Count: 3, Items: 750, UnitPrice: 125, Surcharge: 5000, Amount: 108_800, DiscountPercent: 50_000, Discount: 5400, Total: 103_400},
+					},
+					Subtotal: 108_800,
+					Discount: 5400,
+					Total:    103_400,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"orderSize":501,"currency":"EUR","lines":[` +
+				`{"size":250,"count":3,"items":750,"unitPrice":"0.0125","surcharge":"0.50","amount":"10.88","discountPercent":"5.00","discount":"0.54","total":"10.34"}` +
+				`],"subtotal":"10.88","discount":"0.54","total":"10.34"}` + "\n",
+		},
+		{
+			name:  "options pass through",
+			query: "?orderSize=10&policy=fewest-packs",
+			mockSetup: func(m *mocks.MockQuoter) {
+				m.EXPECT().Quote(gomock.Any(), int64(10), domain.CalculateOptions{Policy: "fewest-packs"}).Return(&domain.Quote{OrderSize: 10}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"orderSize":10,"currency":"","lines":null,"subtotal":"0.00","discount":"0.00","total":"0.00"}` + "\n",
+		},
+		{
+			name:           "invalid order size",
+			query:          "?orderSize=abc",
+			mockSetup:      func(m *mocks.MockQuoter) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid order size\n",
+		},
+		{
+			name:           "invalid option",
+			query:          "?orderSize=10&exact=maybe",
+			mockSetup:      func(m *mocks.MockQuoter) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid exact flag\n",
+		},
+		{
+			name:  "missing prices",
+			query: "?orderSize=10",
+			mockSetup: func(m *mocks.MockQuoter) {
+				m.EXPECT().Quote(gomock.Any(), int64(10), domain.CalculateOptions{}).Return(nil, domain.ErrMissingPackPrices)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   domain.ErrMissingPackPrices.Error() + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuoter := mocks.NewMockQuoter(ctrl)
+			tt.mockSetup(mockQuoter)
+
+			handler := NewQuoteHandler(mockQuoter)
+
+			req := httptest.NewRequest("GET", "/api/quote"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.Quote(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultReportOrderSize = 10_000
+
+//go:generate mockgen -destination=mocks/mock_pack_set_reporter.go -package=mocks calculate_product_packs/internal/transport/http PackSetReporter
+type PackSetReporter interface {
+	Report(ctx context.Context, sizes []domain.PackSize, upTo int64) (*domain.PackSetReport, error)
+	BreakpointTable(ctx context.Context, upTo int64) (*domain.BreakpointTable, error)
+}
+
+//go:generate mockgen -destination=mocks/mock_pack_set_recommender.go -package=mocks calculate_product_packs/internal/transport/http PackSetRecommender
+type PackSetRecommender interface {
+	Recommend(ctx context.Context, req domain.RecommendationRequest) (*domain.Recommendation, error)
+}
+
+//go:generate mockgen -destination=mocks/mock_pack_set_simulator.go -package=mocks calculate_product_packs/internal/transport/http PackSetSimulator
+type PackSetSimulator interface {
+	Simulate(ctx context.Context, req domain.SimulationRequest) (*domain.Simulation, error)
+}
+
+type ReportHandler struct {
+	reporter    PackSetReporter
+	recommender PackSetRecommender
+	simulator   PackSetSimulator
+}
+
+func NewReportHandler(reporter PackSetReporter, recommender PackSetRecommender, simulator PackSetSimulator) *ReportHandler {
+	return &ReportHandler{reporter: reporter, recommender: recommender, simulator: simulator}
+}
+
+// CurrentReport reports on the current pack set for the orders 1..upTo.
+func (h *ReportHandler) CurrentReport(w http.ResponseWriter, r *http.Request) {
+	h.report(w, r, nil)
+}
+
+// ProposedReport reports on the pack sizes in the body without storing them.
+func (h *ReportHandler) ProposedReport(w http.ResponseWriter, r *http.Request) {
+	var sizes []domain.PackSize
+	if err := json.NewDecoder(r.Body).Decode(&sizes); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(sizes) == 0 {
+		http.Error(w, domain.ErrEmptyPackSizes.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.report(w, r, sizes)
+}
+
+func (h *ReportHandler) report(w http.ResponseWriter, r *http.Request, sizes []domain.PackSize) {
+	upTo, err := int64Param(r, "upTo")
+	if err != nil {
+		http.Error(w, "Invalid report range", http.StatusBadRequest)
+		return
+	}
+	if upTo == nil {
+		n := int64(defaultReportOrderSize)
+		upTo = &n
+	}
+
+	report, err := h.reporter.Report(r.Context(), sizes, *upTo)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, report)
+}
+
+// BreakpointTable lists the packing of every order from 1 to upTo for the
+// current pack set: as JSON, or with format=csv or format=markdown as a sheet
+// to print.
+func (h *ReportHandler) BreakpointTable(w http.ResponseWriter, r *http.Request) {
+	upTo, err := int64Param(r, "upTo")
+	if err != nil {
+		http.Error(w, "Invalid report range", http.StatusBadRequest)
+		return
+	}
+	if upTo == nil {
+		n := int64(defaultReportOrderSize)
+		upTo = &n
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" && format != "markdown" {
+		http.Error(w, "Invalid format", http.StatusBadRequest)
+		return
+	}
+
+	table, err := h.reporter.BreakpointTable(r.Context(), *upTo)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	switch format {
+	case "csv":
+		writeBreakpointCSV(w, table)
+	case "markdown":
+		writeBreakpointMarkdown(w, table)
+	default:
+		writeJSON(w, table)
+	}
+}
+
+// writeBreakpointCSV writes one row per interval with a column of pack counts
+// per size, largest first.
+func writeBreakpointCSV(w http.ResponseWriter, table *domain.BreakpointTable) {
+	header := []string{"from", "to", "totalItems", "packCount"}
+	for i := len(table.PackSizes) - 1; i >= 0; i-- {
+		header = append(header, fmt.Sprintf("packs of %d", table.PackSizes[i]))
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+	for _, interval := range table.Intervals {
+		counts := make(map[domain.PackSize]int64, len(interval.Packs))
+		for _, p := range interval.Packs {
+			counts[p.Size] = p.Count
+		}
+		row := []string{
+			strconv.FormatInt(interval.From, 10),
+			strconv.FormatInt(interval.To, 10),
+			strconv.FormatInt(interval.TotalItems, 10),
+			strconv.FormatInt(interval.PackCount, 10),
+		}
+		for i := len(table.PackSizes) - 1; i >= 0; i-- {
+			row = append(row, strconv.FormatInt(counts[table.PackSizes[i]], 10))
+		}
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		slog.Error("failed to write CSV response", "error", err)
+	}
+}
+
+// writeBreakpointMarkdown writes a table that reads like "251–500 → 1×500".
+func writeBreakpointMarkdown(w http.ResponseWriter, table *domain.BreakpointTable) {
+	var b strings.Builder
+	b.WriteString("| Orders | Packs | Total items |\n")
+	b.WriteString("|--------|-------|-------------|\n")
+	for _, interval := range table.Intervals {
+		orders := strconv.FormatInt(interval.From, 10)
+		if interval.To > interval.From {
+			orders += "–" + strconv.FormatInt(interval.To, 10)
+		}
+		packs := make([]string, len(interval.Packs))
+		for i, p := range interval.Packs {
+			packs[i] = fmt.Sprintf("%d×%d", p.Count, p.Size)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d |\n", orders, strings.Join(packs, " + "), interval.TotalItems)
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(b.String())); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+// Recommend searches for pack sizes that serve the workload in the body, or
+// the recorded order history, better than the current ones.
+func (h *ReportHandler) Recommend(w http.ResponseWriter, r *http.Request) {
+	var req domain.RecommendationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.recommender.Recommend(r.Context(), req)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// Simulate compares the workload in the body under the current and a
+// proposed pack set, without storing the proposal.
+func (h *ReportHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var req domain.SimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.simulator.Simulate(r.Context(), req)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, result)
+}
@@ -0,0 +1,382 @@
+package http
+
+import (
+	"bytes"
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/transport/http/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReportHandler(t *testing.T) {
+	report := &domain.PackSetReport{
+		PackSizes:        []domain.PackSize{250},
+		UpTo:             250,
+		ExactOrders:      1,
+		AverageOvershoot: 124.5,
+		WorstOvershoot:   249,
+		WorstOrderSize:   1,
+		UnusedSizes:      []domain.PackSize{},
+		Breakpoints:      []domain.Breakpoint{{OrderSize: 1, Packs: []domain.PackResult{{Size: 250, Count: 1}}, TotalItems: 250, PackCount: 1}},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		body           string
+		mockSetup      func(m *mocks.MockPackSetReporter)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "current pack set",
+			method: "GET",
+			query:  "?upTo=250",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), nil, int64(250)).Return(report, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"packSizes":[250],"upTo":250,"exactOrders":1,"averageOvershoot":124.5,"worstOvershoot":249,"worstOrderSize":1,"unusedSizes":[],
+				"breakpoints":[{"orderSize":1,"packs":[{"size":250,"count":1}],"totalItems":250,"packCount":1}]}`,
+		},
+		{
+			name:   "default range",
+			method: "GET",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), nil, int64(defaultReportOrderSize)).Return(report, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "proposed pack set",
+			method: "POST",
+			query:  "?upTo=250",
+			body:   `[250, 500]`,
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), []domain.PackSize{250, 500}, int64(250)).Return(report, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid range",
+			method:         "GET",
+			query:          "?upTo=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "range out of bounds",
+			method: "GET",
+			query:  "?upTo=0",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), nil, int64(0)).Return(nil, domain.ErrInvalidReportRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty proposed set",
+			method:         "POST",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid proposed size",
+			method: "POST",
+			body:   `[250, -1]`,
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), []domain.PackSize{250, -1}, int64(defaultReportOrderSize)).Return(nil, domain.ErrInvalidPackSize)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "no pack sizes",
+			method: "GET",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().Report(gomock.Any(), nil, int64(defaultReportOrderSize)).Return(nil, domain.ErrNoPackSizes)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReporter := mocks.NewMockPackSetReporter(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockReporter)
+			}
+			handler := NewReportHandler(mockReporter, nil, nil)
+
+			req := httptest.NewRequest(tt.method, "/api/pack-sizes/report"+tt.query, bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			if tt.method == "POST" {
+				handler.ProposedReport(rr, req)
+			} else {
+				handler.CurrentReport(rr, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestReportHandler_BreakpointTable(t *testing.T) {
+	table := &domain.BreakpointTable{
+		PackSizes:      []domain.PackSize{250, 500},
+		PackSetVersion: 3,
+		UpTo:           600,
+		Intervals: []domain.BreakpointInterval{
+			{From: 1, To: 250, Packs: []domain.PackResult{{Size: 250, Count: 1}}, TotalItems: 250, PackCount: 1},
+			{From: 251, To: 500, Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, PackCount: 1},
+			{From: 501, To: 600, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, PackCount: 2},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockSetup           func(m *mocks.MockPackSetReporter)
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:  "json",
+			query: "?upTo=600",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().BreakpointTable(gomock.Any(), int64(600)).Return(table, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: `{"packSizes":[250,500],"packSetVersion":3,"upTo":600,"intervals":[` +
+				`{"from":1,"to":250,"packs":[{"size":250,"count":1}],"totalItems":250,"packCount":1},` +
+				`{"from":251,"to":500,"packs":[{"size":500,"count":1}],"totalItems":500,"packCount":1},` +
+				`{"from":501,"to":600,"packs":[{"size":500,"count":1},{"size":250,"count":1}],"totalItems":750,"packCount":2}]}` + "\n",
+		},
+		{
+			name:  "csv",
+			query: "?upTo=600&format=csv",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().BreakpointTable(gomock.Any(), int64(600)).Return(table, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "from,to,totalItems,packCount,packs of 500,packs of 250\n" +
+				"1,250,250,1,0,1\n" +
+				"251,500,500,1,1,0\n" +
+				"501,600,750,2,1,1\n",
+		},
+		{
+			name:  "markdown",
+			query: "?upTo=600&format=markdown",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().BreakpointTable(gomock.Any(), int64(600)).Return(table, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/markdown; charset=utf-8",
+			expectedBody: "| Orders | Packs | Total items |\n" +
+				"|--------|-------|-------------|\n" +
+				"| 1–250 | 1×250 | 250 |\n" +
+				"| 251–500 | 1×500 | 500 |\n" +
+				"| 501–600 | 1×500 + 1×250 | 750 |\n",
+		},
+		{
+			name: "default range",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().BreakpointTable(gomock.Any(), int64(defaultReportOrderSize)).Return(table, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			name:           "invalid format",
+			query:          "?format=pdf",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid format\n",
+		},
+		{
+			name:           "invalid range",
+			query:          "?upTo=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid report range\n",
+		},
+		{
+			name:  "no pack sizes",
+			query: "?format=csv",
+			mockSetup: func(m *mocks.MockPackSetReporter) {
+				m.EXPECT().BreakpointTable(gomock.Any(), int64(defaultReportOrderSize)).Return(nil, domain.ErrNoPackSizes)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no pack sizes available\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReporter := mocks.NewMockPackSetReporter(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockReporter)
+			}
+			handler := NewReportHandler(mockReporter, nil, nil)
+
+			req := httptest.NewRequest("GET", "/api/pack-sizes/breakpoints"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			handler.BreakpointTable(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, rr.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestReportHandler_Recommend(t *testing.T) {
+	req := domain.RecommendationRequest{Orders: []domain.WeightedOrder{{OrderSize: 300, Weight: 2}}, Count: 1, MinSize: 100, MaxSize: 1000}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSetRecommender)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid request",
+			body: `{"orders":[{"orderSize":300,"weight":2}],"count":1,"minSize":100,"maxSize":1000}`,
+			mockSetup: func(m *mocks.MockPackSetRecommender) {
+				m.EXPECT().Recommend(gomock.Any(), req).Return(&domain.Recommendation{
+					Recommended: domain.PackSetScore{PackSizes: []domain.PackSize{300}, TotalItems: 600, Overshoot: 0, PackCount: 2, Packs: []domain.PackResult{{Size: 300, Count: 2}}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"recommended":{"packSizes":[300],"totalItems":600,"overshoot":0,"packCount":2,"packs":[{"size":300,"count":2}]},
+				"current":null,"overshootSaved":0,"packsSaved":0}`,
+		},
+		{
+			name:           "invalid body",
+			body:           `{"count":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid request",
+			body: `{"orders":[{"orderSize":300,"weight":2}],"count":1,"minSize":100,"maxSize":1000}`,
+			mockSetup: func(m *mocks.MockPackSetRecommender) {
+				m.EXPECT().Recommend(gomock.Any(), req).Return(nil, domain.ErrInvalidRecommendation)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "no workload",
+			body: `{"orders":[{"orderSize":300,"weight":2}],"count":1,"minSize":100,"maxSize":1000}`,
+			mockSetup: func(m *mocks.MockPackSetRecommender) {
+				m.EXPECT().Recommend(gomock.Any(), req).Return(nil, domain.ErrEmptyWorkload)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRecommender := mocks.NewMockPackSetRecommender(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRecommender)
+			}
+			handler := NewReportHandler(nil, mockRecommender, nil)
+
+			rr := httptest.NewRecorder()
+			handler.Recommend(rr, httptest.NewRequest("POST", "/api/pack-sizes/recommendation", bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestReportHandler_Simulate(t *testing.T) {
+	req := domain.SimulationRequest{PackSizes: []domain.PackSize{300}, Range: &domain.OrderRange{From: 1, To: 300}}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func(m *mocks.MockPackSetSimulator)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid request",
+			body: `{"packSizes":[300],"range":{"from":1,"to":300}}`,
+			mockSetup: func(m *mocks.MockPackSetSimulator) {
+				m.EXPECT().Simulate(gomock.Any(), req).Return(&domain.Simulation{
+					Proposed:      domain.PackSetScore{PackSizes: []domain.PackSize{300}, TotalItems: 90000, Overshoot: 44850, PackCount: 300, Packs: []domain.PackResult{{Size: 300, Count: 300}}},
+					ChangedOrders: []int64{},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"current":null,"proposed":{"packSizes":[300],"totalItems":90000,"overshoot":44850,"packCount":300,
+				"packs":[{"size":300,"count":300}]},"changedOrders":[]}`,
+		},
+		{
+			name:           "invalid body",
+			body:           `[300]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid pack size",
+			body: `{"packSizes":[300],"range":{"from":1,"to":300}}`,
+			mockSetup: func(m *mocks.MockPackSetSimulator) {
+				m.EXPECT().Simulate(gomock.Any(), req).Return(nil, domain.ErrInvalidPackSize)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid workload",
+			body: `{"packSizes":[300],"range":{"from":1,"to":300}}`,
+			mockSetup: func(m *mocks.MockPackSetSimulator) {
+				m.EXPECT().Simulate(gomock.Any(), req).Return(nil, domain.ErrInvalidWorkload)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSimulator := mocks.NewMockPackSetSimulator(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockSimulator)
+			}
+			handler := NewReportHandler(nil, nil, mockSimulator)
+
+			rr := httptest.NewRecorder()
+			handler.Simulate(rr, httptest.NewRequest("POST", "/api/pack-sizes/simulation", bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -6,12 +6,44 @@ import (
 	"net/http"
 )
 
-func NewRouter(handler *PackCalculatorHandler, tmpl *template.Template) http.Handler {
+func NewRouter(handler *PackCalculatorHandler, orders *OrderHandler, reports *ReportHandler, warehouses *WarehouseHandler, quotes *QuoteHandler, tmpl *template.Template) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /api/calculate", handler.CalculatePacks)
+	mux.HandleFunc("GET /api/calculate/alternatives", handler.CalculateAlternatives)
+	mux.HandleFunc("GET /api/calculate/shipments", handler.PlanShipments)
+	mux.HandleFunc("GET /api/calculate/packaging", handler.PlanPackaging)
+	mux.HandleFunc("POST /api/calculate/batch", handler.CalculateBatch)
+	mux.HandleFunc("GET /api/calculate/stats", handler.GetStats)
 	mux.HandleFunc("GET /api/pack-sizes", handler.GetPackSizes)
 	mux.HandleFunc("PUT /api/pack-sizes", handler.UpdatePackSizes)
+	mux.HandleFunc("GET /api/pack-sizes/analysis", handler.AnalyzePackSizes)
+	mux.HandleFunc("GET /api/pack-sizes/report", reports.CurrentReport)
+	mux.HandleFunc("POST /api/pack-sizes/report", reports.ProposedReport)
+	mux.HandleFunc("GET /api/pack-sizes/breakpoints", reports.BreakpointTable)
+	mux.HandleFunc("POST /api/pack-sizes/recommendation", reports.Recommend)
+	mux.HandleFunc("POST /api/pack-sizes/simulation", reports.Simulate)
+	mux.HandleFunc("GET /api/stock", handler.GetStock)
+	mux.HandleFunc("PUT /api/stock", handler.UpdateStock)
+	mux.HandleFunc("GET /api/pack-costs", handler.GetCosts)
+	mux.HandleFunc("PUT /api/pack-costs", handler.UpdateCosts)
+	mux.HandleFunc("GET /api/pack-constraints", handler.GetConstraints)
+	mux.HandleFunc("PUT /api/pack-constraints", handler.UpdateConstraints)
+	mux.HandleFunc("GET /api/pack-dimensions", handler.GetDimensions)
+	mux.HandleFunc("PUT /api/pack-dimensions", handler.UpdateDimensions)
+	mux.HandleFunc("GET /api/packaging", handler.GetHierarchies)
+	mux.HandleFunc("PUT /api/packaging", handler.UpdateHierarchies)
+	mux.HandleFunc("GET /api/pricing", handler.GetPricing)
+	mux.HandleFunc("PUT /api/pricing", handler.UpdatePricing)
+	mux.HandleFunc("GET /api/quote", quotes.Quote)
+	mux.HandleFunc("POST /api/calculate/order", orders.CalculateOrder)
+	mux.HandleFunc("GET /api/products", orders.GetProducts)
+	mux.HandleFunc("PUT /api/products/{id}", orders.SaveProduct)
+	mux.HandleFunc("DELETE /api/products/{id}", orders.DeleteProduct)
+	mux.HandleFunc("POST /api/calculate/warehouses", warehouses.SplitOrder)
+	mux.HandleFunc("GET /api/warehouses", warehouses.GetWarehouses)
+	mux.HandleFunc("PUT /api/warehouses/{id}", warehouses.SaveWarehouse)
+	mux.HandleFunc("DELETE /api/warehouses/{id}", warehouses.DeleteWarehouse)
 
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, map[string]string{"status": "ok"})
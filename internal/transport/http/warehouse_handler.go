@@ -0,0 +1,114 @@
+package http
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+//go:generate mockgen -destination=mocks/mock_order_splitter.go -package=mocks calculate_product_packs/internal/transport/http OrderSplitter
+type OrderSplitter interface {
+	Execute(ctx context.Context, quantity int64, preferences []domain.WarehousePreference, opts domain.CalculateOptions) (*domain.WarehouseSplit, error)
+}
+
+//go:generate mockgen -destination=mocks/mock_warehouse_catalog.go -package=mocks calculate_product_packs/internal/transport/http WarehouseCatalog
+type WarehouseCatalog interface {
+	GetWarehouses() []domain.Warehouse
+	SaveWarehouse(warehouse domain.Warehouse) error
+	DeleteWarehouse(id string) error
+}
+
+type WarehouseHandler struct {
+	orderSplitter    OrderSplitter
+	warehouseCatalog WarehouseCatalog
+}
+
+func NewWarehouseHandler(orderSplitter OrderSplitter, warehouseCatalog WarehouseCatalog) *WarehouseHandler {
+	return &WarehouseHandler{
+		orderSplitter:    orderSplitter,
+		warehouseCatalog: warehouseCatalog,
+	}
+}
+
+type splitRequest struct {
+	Quantity   int64                        `json:"quantity"`
+	Warehouses []domain.WarehousePreference `json:"warehouses"`
+}
+
+// SplitOrder splits an order across warehouses. It takes the same query
+// parameters as CalculatePacks, applied to every warehouse's share.
+func (h *WarehouseHandler) SplitOrder(w http.ResponseWriter, r *http.Request) {
+	var req splitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opts, ok := calculateOptions(w, r)
+	if !ok {
+		return
+	}
+
+	split, err := h.orderSplitter.Execute(r.Context(), req.Quantity, req.Warehouses, opts)
+	if err != nil {
+		writeCalculationError(w, err)
+		return
+	}
+
+	writeJSON(w, split)
+}
+
+func (h *WarehouseHandler) GetWarehouses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.warehouseCatalog.GetWarehouses())
+}
+
+type warehouseRequest struct {
+	PackSizes []domain.PackSize  `json:"packSizes"`
+	Stock     []domain.PackStock `json:"stock"`
+}
+
+// SaveWarehouse stores the pack sizes and stock in the body under the
+// warehouse ID in the path.
+func (h *WarehouseHandler) SaveWarehouse(w http.ResponseWriter, r *http.Request) {
+	var req warehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	warehouse := domain.Warehouse{ID: r.PathValue("id"), PackSizes: req.PackSizes, Stock: req.Stock}
+	if err := h.warehouseCatalog.SaveWarehouse(warehouse); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidWarehouseID),
+			errors.Is(err, domain.ErrEmptyPackSizes),
+			errors.Is(err, domain.ErrInvalidPackSize),
+			errors.Is(err, domain.ErrTooManyPackSizes),
+			errors.Is(err, domain.ErrInvalidStock):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to save warehouse", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("Warehouse saved successfully")); err != nil {
+		slog.Error("failed to write response", "error", err)
+	}
+}
+
+func (h *WarehouseHandler) DeleteWarehouse(w http.ResponseWriter, r *http.Request) {
+	if err := h.warehouseCatalog.DeleteWarehouse(r.PathValue("id")); err != nil {
+		if errors.Is(err, domain.ErrWarehouseNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete warehouse", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"bytes"
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/transport/http/mocks"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWarehouseHandler_SplitOrder(t *testing.T) {
+	preferences := []domain.WarehousePreference{{WarehouseID: "north", Cost: 1}, {WarehouseID: "east", Cost: 5}}
+
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		mockSetup      func(m *mocks.MockOrderSplitter)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid split",
+			body: `{"quantity":1600,"warehouses":[{"warehouseId":"north","cost":1},{"warehouseId":"east","cost":5}]}`,
+			mockSetup: func(m *mocks.MockOrderSplitter) {
+				m.EXPECT().Execute(gomock.Any(), int64(1600), preferences, domain.CalculateOptions{}).Return(&domain.WarehouseSplit{
+					Quantity: 1600,
+					Allocations: []domain.WarehouseAllocation{
+						{WarehouseID: "north", Quantity: 1300, Packs: []domain.PackResult{{Size: 700, Count: 1}, {Size: 300, Count: 2}}, TotalItems: 1300, PackCount: 3},
+						{WarehouseID: "east", Quantity: 300, Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, Overshoot: 200, PackCount: 1},
+					},
+					TotalItems: 1800,
+					Overshoot:  200,
+					PackCount:  4,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"quantity":1600,"allocations":[` +
+				`{"warehouseId":"north","quantity":1300,"packs":[{"size":700,"count":1},{"size":300,"count":2}],"totalItems":1300,"overshoot":0,"packCount":3},` +
+				`{"warehouseId":"east","quantity":300,"packs":[{"size":500,"count":1}],"totalItems":500,"overshoot":200,"packCount":1}` +
+				`],"totalItems":1800,"overshoot":200,"packCount":4}` + "\n",
+		},
+		{
+			name:  "options apply to every warehouse",
+			query: "?policy=fewest-packs",
+			body:  `{"quantity":10}`,
+			mockSetup: func(m *mocks.MockOrderSplitter) {
+				m.EXPECT().Execute(gomock.Any(), int64(10), nil, domain.CalculateOptions{Policy: "fewest-packs"}).Return(&domain.WarehouseSplit{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"quantity":0,"allocations":null,"totalItems":0,"overshoot":0,"packCount":0}` + "\n",
+		},
+		{
+			name:           "invalid JSON",
+			body:           `not json`,
+			mockSetup:      func(m *mocks.MockOrderSplitter) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name:           "invalid option",
+			query:          "?exact=maybe",
+			body:           `{"quantity":10}`,
+			mockSetup:      func(m *mocks.MockOrderSplitter) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid exact flag\n",
+		},
+		{
+			name: "unknown warehouse",
+			body: `{"quantity":10,"warehouses":[{"warehouseId":"west"}]}`,
+			mockSetup: func(m *mocks.MockOrderSplitter) {
+				m.EXPECT().Execute(gomock.Any(), int64(10), []domain.WarehousePreference{{WarehouseID: "west"}}, domain.CalculateOptions{}).
+					Return(nil, fmt.Errorf("%w: west", domain.ErrWarehouseNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "warehouse not found: west\n",
+		},
+		{
+			name: "invalid preferences",
+			body: `{"quantity":10,"warehouses":[{"warehouseId":"north","cost":-1}]}`,
+			mockSetup: func(m *mocks.MockOrderSplitter) {
+				m.EXPECT().Execute(gomock.Any(), int64(10), []domain.WarehousePreference{{WarehouseID: "north", Cost: -1}}, domain.CalculateOptions{}).
+					Return(nil, domain.ErrInvalidWarehousePreference)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidWarehousePreference.Error() + "\n",
+		},
+		{
+			name: "warehouses fall short",
+			body: `{"quantity":2000}`,
+			mockSetup: func(m *mocks.MockOrderSplitter) {
+				m.EXPECT().Execute(gomock.Any(), int64(2000), nil, domain.CalculateOptions{}).Return(nil, &domain.InsufficientStockError{
+					OrderSize: 2000,
+					Available: 1300,
+					Shortfall: 700,
+					Packs:     []domain.PackResult{{Size: 700, Count: 1}, {Size: 300, Count: 2}},
+				})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"orderSize":2000,"available":1300,"shortfall":700,"packs":[{"size":700,"count":1},{"size":300,"count":2}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSplitter := mocks.NewMockOrderSplitter(ctrl)
+			tt.mockSetup(mockSplitter)
+
+			handler := NewWarehouseHandler(mockSplitter, nil)
+
+			req := httptest.NewRequest("POST", "/api/calculate/warehouses"+tt.query, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.SplitOrder(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestWarehouseHandler_SaveWarehouse(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		mockSetup      func(m *mocks.MockWarehouseCatalog)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "valid warehouse",
+			id:   "north",
+			body: `{"packSizes":[300,700],"stock":[{"size":300,"available":2}]}`,
+			mockSetup: func(m *mocks.MockWarehouseCatalog) {
+				m.EXPECT().SaveWarehouse(domain.Warehouse{
+					ID:        "north",
+					PackSizes: []domain.PackSize{300, 700},
+					Stock:     []domain.PackStock{{Size: 300, Available: 2}},
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Warehouse saved successfully",
+		},
+		{
+			name:           "invalid JSON",
+			id:             "north",
+			body:           `[300]`,
+			mockSetup:      func(m *mocks.MockWarehouseCatalog) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body\n",
+		},
+		{
+			name: "invalid stock",
+			id:   "north",
+			body: `{"packSizes":[300],"stock":[{"size":300,"available":-1}]}`,
+			mockSetup: func(m *mocks.MockWarehouseCatalog) {
+				m.EXPECT().SaveWarehouse(gomock.Any()).Return(domain.ErrInvalidStock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidStock.Error() + "\n",
+		},
+		{
+			name: "invalid warehouse ID",
+			id:   "a b",
+			body: `{"packSizes":[300]}`,
+			mockSetup: func(m *mocks.MockWarehouseCatalog) {
+				m.EXPECT().SaveWarehouse(domain.Warehouse{ID: "a b", PackSizes: []domain.PackSize{300}}).Return(domain.ErrInvalidWarehouseID)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   domain.ErrInvalidWarehouseID.Error() + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCatalog := mocks.NewMockWarehouseCatalog(ctrl)
+			tt.mockSetup(mockCatalog)
+
+			handler := NewWarehouseHandler(nil, mockCatalog)
+
+			req := httptest.NewRequest("PUT", "/api/warehouses/x", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", tt.id)
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.SaveWarehouse(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestWarehouseHandler_DeleteWarehouse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCatalog := mocks.NewMockWarehouseCatalog(ctrl)
+	mockCatalog.EXPECT().DeleteWarehouse("north").Return(nil)
+	mockCatalog.EXPECT().DeleteWarehouse("west").Return(domain.ErrWarehouseNotFound)
+
+	handler := NewWarehouseHandler(nil, mockCatalog)
+
+	req := httptest.NewRequest("DELETE", "/api/warehouses/north", nil)
+	req.SetPathValue("id", "north")
+	rr := httptest.NewRecorder()
+	handler.DeleteWarehouse(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/warehouses/west", nil)
+	req.SetPathValue("id", "west")
+	rr = httptest.NewRecorder()
+	handler.DeleteWarehouse(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "warehouse not found\n", rr.Body.String())
+}
+
+func TestWarehouseHandler_GetWarehouses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCatalog := mocks.NewMockWarehouseCatalog(ctrl)
+	mockCatalog.EXPECT().GetWarehouses().Return([]domain.Warehouse{
+		{ID: "north", PackSizes: []domain.PackSize{300, 700}, Stock: []domain.PackStock{{Size: 300, Available: 2}}},
+	})
+
+	handler := NewWarehouseHandler(nil, mockCatalog)
+
+	req := httptest.NewRequest("GET", "/api/warehouses", nil)
+	rr := httptest.NewRecorder()
+	handler.GetWarehouses(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `[{"id":"north","packSizes":[300,700],"stock":[{"size":300,"available":2}]}]`+"\n", rr.Body.String())
+}
@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"maps"
+	"math"
+)
+
+const maxAlternatives = 10
+
+// Alternatives returns up to k packings for the order ranked by the
+// FewestItems policy, whatever the default: fewest items first, then fewest
+// packs. The first one is the packing Execute returns under that policy; for
+// every total, its fewest-pack packing comes before the other packings of
+// that total.
+func (uc *CalculatePacksUseCase) Alternatives(ctx context.Context, orderSize int64, k int) ([]domain.Alternative, error) {
+	if k < 1 || k > maxAlternatives {
+		return nil, domain.ErrInvalidAlternativeCount
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.computeBudget)
+	defer cancel()
+
+	table, err := uc.table(ctx, orderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	packings, err := rankedPackings(ctx, orderSize, k, table)
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := make([]domain.Alternative, 0, len(packings))
+	for _, counts := range packings {
+		total := int64(0)
+		for size, n := range counts {
+			total += int64(size) * n
+		}
+		alternatives = append(alternatives, domain.Alternative{
+			Packs:      toPackResults(counts),
+			TotalItems: total,
+			PackCount:  sumCounts(counts),
+		})
+	}
+	return alternatives, nil
+}
+
+// rankedPackings walks the shippable totals from the optimal one upwards and
+// collects the packings of each by increasing pack count until it has k. It
+// returns fewer when the totals run into the int64 limit, and
+// ErrTotalOverflow when not even the optimal one fits.
+func rankedPackings(ctx context.Context, orderSize int64, k int, table *packTable) ([]map[int]int64, error) {
+	var packings []map[int]int64
+
+	total, ok := table.smallestTotal(orderSize)
+	if !ok {
+		return nil, domain.ErrTotalOverflow
+	}
+	for ; ok && len(packings) < k; total, ok = nextTotal(table, total) {
+		best, _, err := table.compose(ctx, total)
+		if err != nil {
+			return nil, err
+		}
+		packings = append(packings, best)
+
+		err = enumeratePackings(ctx, table.sizes, total, sumCounts(best), func(counts map[int]int64) bool {
+			if !maps.Equal(counts, best) {
+				packings = append(packings, counts)
+			}
+			return len(packings) < k
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return packings, nil
+}
+
+// nextTotal returns the smallest shippable total above total, or false if
+// there is none within the int64 range.
+func nextTotal(table *packTable, total int64) (int64, bool) {
+	if total == math.MaxInt64 {
+		return 0, false
+	}
+	return table.smallestTotal(total + 1)
+}
+
+// enumeratePackings calls yield with every packing of exactly total items that
+// uses at least minPacks packs, ordered by pack count and, within a count,
+// from the most large packs down, until yield returns false. sizes must be
+// ascending.
+//
+// The search fixes the count of each size from the largest down and prunes
+// any branch whose remaining items cannot be split into the remaining packs,
+// so it only descends into count ranges that can still fit. The bounds are
+// compared by division, as pack counts times sizes may exceed int64.
+func enumeratePackings(ctx context.Context, sizes []int, total, minPacks int64, yield func(map[int]int64) bool) error {
+	counts := make([]int64, len(sizes))
+	smallest := int64(sizes[0])
+	stopped := false
+	steps := 0
+
+	var walk func(i int, remaining, packs int64) error
+	walk = func(i int, remaining, packs int64) error {
+		steps++
+		if steps%cancelCheckInterval == 0 {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+		}
+
+		size := int64(sizes[i])
+		if packs > remaining/smallest || packs < ceilDiv(remaining, size) {
+			return nil
+		}
+		if i == 0 {
+			counts[0] = packs
+			if !yield(packingCounts(sizes, counts)) {
+				stopped = true
+			}
+			counts[0] = 0
+			return nil
+		}
+
+		// Whatever this size leaves must fit between packs-c smallest and
+		// packs-c next-smaller packs.
+		next := int64(sizes[i-1])
+		hi := min(packs, (remaining-packs*smallest)/(size-smallest))
+		lo := int64(0)
+		if packs < ceilDiv(remaining, next) {
+			lo = ceilDiv(remaining-packs*next, size-next)
+		}
+
+		for c := hi; c >= lo && !stopped; c-- {
+			counts[i] = c
+			if err := walk(i-1, remaining-c*size, packs-c); err != nil {
+				return err
+			}
+		}
+		counts[i] = 0
+		return nil
+	}
+
+	for packs := minPacks; packs <= total/smallest && !stopped; packs++ {
+		if err := walk(len(sizes)-1, total, packs); err != nil {
+			return err
+		}
+		if packs == math.MaxInt64 {
+			break
+		}
+	}
+	return nil
+}
+
+func packingCounts(sizes []int, counts []int64) map[int]int64 {
+	m := make(map[int]int64)
+	for i, n := range counts {
+		if n > 0 {
+			m[sizes[i]] = n
+		}
+	}
+	return m
+}
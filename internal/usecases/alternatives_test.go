@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalculatePacksUseCase_Alternatives(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []domain.PackSize
+		orderSize int64
+		k         int
+		expected  []domain.Alternative
+	}{
+		{
+			name:      "same total with a different mix, then the next total",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 251,
+			k:         3,
+			expected: []domain.Alternative{
+				{Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, PackCount: 1},
+				{Packs: []domain.PackResult{{Size: 250, Count: 2}}, TotalItems: 500, PackCount: 2},
+				{Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, PackCount: 2},
+			},
+		},
+		{
+			name:      "single best packing",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 12001,
+			k:         1,
+			expected: []domain.Alternative{
+				{
+					Packs:      []domain.PackResult{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+					TotalItems: 12250,
+					PackCount:  4,
+				},
+			},
+		},
+		{
+			name:      "single pack size moves on to the next total",
+			packSizes: []domain.PackSize{1000},
+			orderSize: 2500,
+			k:         2,
+			expected: []domain.Alternative{
+				{Packs: []domain.PackResult{{Size: 1000, Count: 3}}, TotalItems: 3000, PackCount: 3},
+				{Packs: []domain.PackResult{{Size: 1000, Count: 4}}, TotalItems: 4000, PackCount: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			mockRepo.EXPECT().GetPackSizes().Return(tt.packSizes)
+
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Alternatives(context.Background(), tt.orderSize, tt.k)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalculatePacksUseCase_Alternatives_Ranking(t *testing.T) {
+	sets := [][]domain.PackSize{
+		{250, 500, 1000, 2000, 5000},
+		{23, 31, 53},
+		{17, 31, 47},
+	}
+
+	for _, set := range sets {
+		for _, orderSize := range []int64{1, 263, 5000, 500000} {
+			t.Run(fmt.Sprint(set, orderSize), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+				mockRepo.EXPECT().GetPackSizes().Return(set).Times(2)
+				mockRepo.EXPECT().GetConstraints().Return(nil)
+
+				useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+				best, err := useCase.Execute(context.Background(), orderSize, domain.CalculateOptions{})
+				require.NoError(t, err)
+
+				result, err := useCase.Alternatives(context.Background(), orderSize, maxAlternatives)
+				require.NoError(t, err)
+				require.Len(t, result, maxAlternatives)
+				assert.Equal(t, best, result[0].Packs)
+
+				seen := make(map[string]bool)
+				for i, alt := range result {
+					total, packs := int64(0), int64(0)
+					for _, p := range alt.Packs {
+						total += int64(p.Size) * p.Count
+						packs += p.Count
+					}
+					assert.Equal(t, total, alt.TotalItems)
+					assert.Equal(t, packs, alt.PackCount)
+					assert.GreaterOrEqual(t, alt.TotalItems, orderSize)
+
+					key := fmt.Sprint(alt.Packs)
+					assert.False(t, seen[key], "duplicate packing %s", key)
+					seen[key] = true
+
+					if i > 0 {
+						prev := result[i-1]
+						assert.True(t, prev.TotalItems < alt.TotalItems ||
+							(prev.TotalItems == alt.TotalItems && prev.PackCount <= alt.PackCount),
+							"%v ranked before %v", prev, alt)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCalculatePacksUseCase_Alternatives_Int64Limit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{1})
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	// Only two totals are left below the limit.
+	result, err := useCase.Alternatives(context.Background(), math.MaxInt64-1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Alternative{
+		{Packs: []domain.PackResult{{Size: 1, Count: math.MaxInt64 - 1}}, TotalItems: math.MaxInt64 - 1, PackCount: math.MaxInt64 - 1},
+		{Packs: []domain.PackResult{{Size: 1, Count: math.MaxInt64}}, TotalItems: math.MaxInt64, PackCount: math.MaxInt64},
+	}, result)
+
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{2})
+	_, err = useCase.Alternatives(context.Background(), math.MaxInt64, 3)
+	assert.ErrorIs(t, err, domain.ErrTotalOverflow)
+}
+
+func TestCalculatePacksUseCase_Alternatives_InvalidCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	for _, k := range []int{0, -1, maxAlternatives + 1} {
+		result, err := useCase.Alternatives(context.Background(), 500, k)
+		assert.ErrorIs(t, err, domain.ErrInvalidAlternativeCount)
+		assert.Empty(t, result)
+	}
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"iter"
+	"sync"
+)
+
+const (
+	// maxBatchOrders bounds the orders one batch may hold.
+	maxBatchOrders = 100_000
+	// batchWindowPerWorker bounds the results held back per worker while an
+	// earlier order is still being packed.
+	batchWindowPerWorker = 4
+)
+
+// ExecuteBatch packs every order like Execute and passes the results to emit
+// in input order. All orders see one snapshot of the pack set and its
+// settings, taken before the first is packed, and with RespectStock each
+// sees the full stock. Orders run on a bounded worker pool, each with its own
+// compute budget.
+//
+// An order that fails, or an error the sequence yields in its place, becomes
+// a result with Err set. ExecuteBatch itself fails only for what stops the
+// whole batch: invalid options, no pack sizes, more than maxBatchOrders
+// orders, a canceled context or an error from emit. It returns once every
+// result emitted so far is out and no worker is left running.
+func (uc *CalculatePacksUseCase) ExecuteBatch(
+	ctx context.Context,
+	orders iter.Seq2[int64, error],
+	opts domain.CalculateOptions,
+	emit func(domain.BatchResult) error,
+) error {
+	policy, err := uc.resolvePolicy(opts)
+	if err != nil {
+		return err
+	}
+
+	st, err := uc.snapshot(ctx, policy, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := max(1, uc.batchWorkers)
+	type job struct {
+		index     int
+		orderSize int64
+		err       error
+	}
+	jobs := make(chan job)
+	results := make(chan domain.BatchResult)
+	// window holds a slot per order read but not yet emitted, so a slow
+	// order cannot make the others pile up.
+	window := make(chan struct{}, workers*batchWindowPerWorker)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for j := range jobs {
+				r := domain.BatchResult{Index: j.index, OrderSize: j.orderSize, Err: j.err}
+				if r.Err == nil {
+					r.Packs, r.Err = uc.batchOrder(ctx, j.orderSize, st, policy, opts)
+				}
+				results <- r
+			}
+		})
+	}
+
+	var readErr error
+	go func() {
+		defer close(jobs)
+		i := 0
+		for orderSize, err := range orders {
+			if i == maxBatchOrders {
+				readErr = domain.ErrTooManyBatchOrders
+				return
+			}
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{index: i, orderSize: orderSize, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			i++
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Results arrive in any order; hold each until those before it are out.
+	pending := make(map[int]domain.BatchResult)
+	next := 0
+	var emitErr error
+	for r := range results {
+		if emitErr != nil {
+			continue
+		}
+		pending[r.Index] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if emitErr = emit(r); emitErr != nil {
+				cancel()
+				break
+			}
+			<-window
+			next++
+		}
+	}
+
+	// results is closed only after jobs, so readErr is settled.
+	switch {
+	case emitErr != nil:
+		return emitErr
+	case readErr != nil:
+		return readErr
+	default:
+		return contextError(ctx)
+	}
+}
+
+// snapshot reads the pack set and the settings solve needs under one
+// PackSetVersion, reading them again when an update lands in between.
+func (uc *CalculatePacksUseCase) snapshot(ctx context.Context, policy Policy, opts domain.CalculateOptions) (*packState, error) {
+	for {
+		version := uc.repo.PackSetVersion()
+		packSizes := uc.repo.GetPackSizes()
+		if len(packSizes) == 0 {
+			return nil, domain.ErrNoPackSizes
+		}
+		buildCtx, cancelBuild := withComputeBudget(ctx, uc.computeBudget)
+		table, err := uc.tables.get(buildCtx, packSizes)
+		cancelBuild()
+		if err != nil {
+			return nil, err
+		}
+		st := uc.state(table, policy, opts)
+		if uc.repo.PackSetVersion() == version {
+			return st, nil
+		}
+	}
+}
+
+// batchOrder packs one order of a batch under its own compute budget.
+func (uc *CalculatePacksUseCase) batchOrder(
+	ctx context.Context,
+	orderSize int64,
+	st *packState,
+	policy Policy,
+	opts domain.CalculateOptions,
+) ([]domain.PackResult, error) {
+	if orderSize <= 0 {
+		return nil, domain.ErrOrderSizePositive
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.computeBudget)
+	defer cancel()
+
+	sol, err := uc.solve(ctx, orderSize, st, policy, opts)
+	if err != nil {
+		return nil, err
+	}
+	uc.record(orderSize)
+	return toPackResults(sol.counts), nil
+}
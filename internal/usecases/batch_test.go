@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// orderSeq yields the order sizes, and err in place of each negative one.
+func orderSeq(orderSizes []int64, err error) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		for _, orderSize := range orderSizes {
+			if orderSize < 0 && !yield(0, err) {
+				return
+			}
+			if orderSize >= 0 && !yield(orderSize, nil) {
+				return
+			}
+		}
+	}
+}
+
+// batchRepo returns a repository holding the pack sizes and nothing else.
+func batchRepo(ctrl *gomock.Controller, sizes ...domain.PackSize) domain.PackSizeRepository {
+	repo := mocks.NewMockPackSizeRepository(ctrl)
+	repo.EXPECT().GetPackSizes().Return(sizes).AnyTimes()
+	repo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	repo.EXPECT().PackSetVersion().Return(uint64(1)).AnyTimes()
+	return repo
+}
+
+func TestCalculatePacksUseCase_ExecuteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// One snapshot serves the whole batch.
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000, 2000, 5000}).Times(1)
+	mockRepo.EXPECT().GetConstraints().Return(nil).Times(1)
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(2)
+
+	orderSizes := make([]int64, 200)
+	for i := range orderSizes {
+		orderSizes[i] = int64(i*37 + 1)
+	}
+	orderSizes[3] = 0
+	errBadOrder := errors.New("bad order")
+	orderSizes[5] = -1
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithBatchWorkers(4))
+	var results []domain.BatchResult
+	err := useCase.ExecuteBatch(context.Background(), orderSeq(orderSizes, errBadOrder), domain.CalculateOptions{}, func(r domain.BatchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, len(orderSizes))
+	single := NewCalculatePacksUseCase(batchRepo(ctrl, 250, 500, 1000, 2000, 5000), NewPackTableCache())
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		switch i {
+		case 3:
+			assert.ErrorIs(t, r.Err, domain.ErrOrderSizePositive)
+		case 5:
+			assert.ErrorIs(t, r.Err, errBadOrder)
+		default:
+			require.NoError(t, r.Err, "order %d", r.OrderSize)
+			want, err := single.Execute(context.Background(), r.OrderSize, domain.CalculateOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, orderSizes[i], r.OrderSize)
+			assert.Equal(t, want, r.Packs, "order %d", r.OrderSize)
+		}
+	}
+}
+
+func TestCalculatePacksUseCase_ExecuteBatchSnapshotRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The pack sizes change while the first snapshot is read, so it is taken
+	// again and every order sees the new set.
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(1)),
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500}),
+		mockRepo.EXPECT().GetConstraints().Return(nil),
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(2)),
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(2)),
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{300}),
+		mockRepo.EXPECT().GetConstraints().Return(nil),
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(2)),
+	)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	var results []domain.BatchResult
+	err := useCase.ExecuteBatch(context.Background(), orderSeq([]int64{250, 501}, nil), domain.CalculateOptions{}, func(r domain.BatchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BatchResult{
+		{Index: 0, OrderSize: 250, Packs: []domain.PackResult{{Size: 300, Count: 1}}},
+		{Index: 1, OrderSize: 501, Packs: []domain.PackResult{{Size: 300, Count: 2}}},
+	}, results)
+}
+
+func TestCalculatePacksUseCase_ExecuteBatchErrors(t *testing.T) {
+	t.Run("no pack sizes", func(t *testing.T) {
+		useCase := NewCalculatePacksUseCase(batchRepo(gomock.NewController(t)), NewPackTableCache())
+		err := useCase.ExecuteBatch(context.Background(), orderSeq([]int64{1}, nil), domain.CalculateOptions{}, func(domain.BatchResult) error {
+			t.Fatal("unexpected result")
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrNoPackSizes)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		useCase := NewCalculatePacksUseCase(batchRepo(gomock.NewController(t), 250), NewPackTableCache())
+		err := useCase.ExecuteBatch(context.Background(), orderSeq([]int64{1}, nil), domain.CalculateOptions{Policy: "nope"}, func(domain.BatchResult) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidPolicy)
+	})
+
+	t.Run("too many orders", func(t *testing.T) {
+		useCase := NewCalculatePacksUseCase(batchRepo(gomock.NewController(t), 250), NewPackTableCache(), WithBatchWorkers(2))
+		orders := func(yield func(int64, error) bool) {
+			for {
+				if !yield(1, nil) {
+					return
+				}
+			}
+		}
+		emitted := 0
+		err := useCase.ExecuteBatch(context.Background(), orders, domain.CalculateOptions{}, func(domain.BatchResult) error {
+			emitted++
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrTooManyBatchOrders)
+		assert.Equal(t, maxBatchOrders, emitted)
+	})
+
+	t.Run("emit error stops the batch", func(t *testing.T) {
+		useCase := NewCalculatePacksUseCase(batchRepo(gomock.NewController(t), 250), NewPackTableCache(), WithBatchWorkers(2))
+		errGone := errors.New("client gone")
+		emitted := 0
+		err := useCase.ExecuteBatch(context.Background(), orderSeq(make([]int64, 1000), nil), domain.CalculateOptions{}, func(domain.BatchResult) error {
+			emitted++
+			if emitted == 3 {
+				return errGone
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errGone)
+		assert.Equal(t, 3, emitted)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		useCase := NewCalculatePacksUseCase(batchRepo(gomock.NewController(t), 250), NewPackTableCache())
+		ctx, cancel := context.WithCancel(context.Background())
+		err := useCase.ExecuteBatch(ctx, orderSeq([]int64{1, 2, 3}, nil), domain.CalculateOptions{}, func(domain.BatchResult) error {
+			cancel()
+			return nil
+		})
+		assert.ErrorIs(t, err, domain.ErrCalculationCanceled)
+	})
+}
@@ -0,0 +1,405 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+)
+
+// maxBoundedEntries caps the cells of the bounded DP: an int32 pack count per
+// amount and pack size, plus an int64 cost when ranking by cost (96 MiB).
+const maxBoundedEntries = 1 << 23
+
+// boundedSearch is a calculation the residue tables cannot answer, because
+// packs are limited by stock or constraints, or the policy is not FewestItems.
+type boundedSearch struct {
+	sizes  []int   // ascending
+	limits []int   // most packs per size, -1 for unlimited
+	costs  []int64 // cost per pack of each size, nil unless the policy uses costs
+	policy Policy
+	// minimums are the fewest packs per size, nil for none. They must be
+	// within limits.
+	minimums []int
+	// maxTotal is the largest total that may be shipped, 0 for no cap.
+	maxTotal int64
+}
+
+// score ranks packings of the same amount: cheapest first, then fewest packs.
+// Without costs only the pack count matters. packs is -1 for amounts that
+// cannot be made.
+type score struct {
+	cost  int64
+	packs int32
+}
+
+func (a score) less(b score) bool {
+	return a.cost < b.cost || (a.cost == b.cost && a.packs < b.packs)
+}
+
+// boundedLayer holds the best score per amount using a prefix of the sizes.
+type boundedLayer struct {
+	packs []int32
+	cost  []int64 // nil without costs
+}
+
+func (l boundedLayer) at(x int) score {
+	s := score{packs: l.packs[x]}
+	if l.cost != nil {
+		s.cost = l.cost[x]
+	}
+	return s
+}
+
+func (l boundedLayer) set(x int, s score) {
+	l.packs[x] = s.packs
+	if l.cost != nil {
+		l.cost[x] = s.cost
+	}
+}
+
+// stockLimits returns how many packs of each size may be used, or -1 where
+// the size has no stock entry. sizes must be ascending.
+func stockLimits(sizes []int, stock []domain.PackStock) []int {
+	available := make(map[int]int, len(stock))
+	for _, entry := range stock {
+		available[int(entry.Size)] = entry.Available
+	}
+
+	limits := make([]int, len(sizes))
+	for i, size := range sizes {
+		limits[i] = -1
+		if n, ok := available[size]; ok {
+			limits[i] = n
+		}
+	}
+	return limits
+}
+
+// packCosts returns the cost of each size, or ErrMissingPackCosts if any size
+// has none. sizes must be ascending.
+func packCosts(sizes []int, costs []domain.PackCost) ([]int64, error) {
+	bySize := make(map[int]int64, len(costs))
+	for _, entry := range costs {
+		bySize[int(entry.Size)] = entry.Cost
+	}
+
+	result := make([]int64, len(sizes))
+	for i, size := range sizes {
+		cost, ok := bySize[size]
+		if !ok {
+			return nil, domain.ErrMissingPackCosts
+		}
+		result[i] = cost
+	}
+	return result, nil
+}
+
+// constraintBounds returns the fewest and most packs of each size an order
+// may use under the constraints, with -1 where there is no most, and false
+// when no constraint applies to the order. Constraints on sizes outside sizes
+// are ignored; sizes must be ascending.
+func constraintBounds(sizes []int, constraints []domain.PackConstraint, orderSize int64) ([]int, []int, bool) {
+	index := make(map[int]int, len(sizes))
+	for i, size := range sizes {
+		index[size] = i
+	}
+
+	var mins, maxes []int
+	for _, c := range constraints {
+		i, ok := index[int(c.Size)]
+		if !ok || orderSize <= c.OrdersOver {
+			continue
+		}
+		if mins == nil {
+			mins, maxes = make([]int, len(sizes)), unlimited(sizes)
+		}
+		mins[i] = max(mins[i], c.Min)
+		if c.Max != nil && (maxes[i] < 0 || *c.Max < maxes[i]) {
+			maxes[i] = *c.Max
+		}
+	}
+	return mins, maxes, mins != nil
+}
+
+// unlimited returns limits that allow any number of packs of every size.
+func unlimited(sizes []int) []int {
+	limits := make([]int, len(sizes))
+	for i := range limits {
+		limits[i] = -1
+	}
+	return limits
+}
+
+// withinLimits reports whether a packing keeps to the minimums and limits of
+// its sizes. Nil minimums or limits allow any packing.
+func withinLimits(counts map[int]int64, sizes, minimums, limits []int) bool {
+	for i, limit := range limits {
+		if limit >= 0 && counts[sizes[i]] > int64(limit) {
+			return false
+		}
+	}
+	for i, n := range minimums {
+		if counts[sizes[i]] < int64(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// compose finds the packing the policy ranks best and returns it with its
+// total. Among packings of equal rank it takes the most of the largest size,
+// then of the next one, and so on. When the limited sizes are all there is
+// and they hold fewer items than the order, it returns an
+// InsufficientStockError; when no total fits under maxTotal, an
+// OvershootError.
+//
+// It runs a DP with one layer per size over the amounts up to
+// orderSize+maxPack-1, which holds the optimum of every Policy (see there).
+// Each layer takes O(amounts) with a sliding-window minimum per
+// residue class of the size, and the layers are kept for reconstruction, so
+// memory is O(k*(orderSize+maxPack)). Only multiples of the sizes' GCD can be
+// shipped, so the amounts are counted in units of it; the policy still ranks
+// totals in items. The minimum packs are taken up front and the DP packs what
+// is left of the order, so it ranks every packing by the totals including
+// them.
+//
+// Without costs and with the largest size unlimited, all but O(maxPack²)
+// units of the order also go in largest packs up front, so orders of any
+// size are answered. Otherwise orders whose DP would pass maxBoundedEntries
+// cells fail with ErrOrderTooLarge.
+func (s boundedSearch) compose(ctx context.Context, orderSize int64) (map[int]int64, int64, error) {
+	sizes := s.sizes
+	g := sizes[0]
+	for _, size := range sizes[1:] {
+		g = gcd(g, size)
+	}
+	unit := int64(g)
+	if g > 1 {
+		s.sizes = make([]int, len(sizes))
+		for i, size := range sizes {
+			s.sizes[i] = size / g
+		}
+	}
+
+	// fixed holds the minimum packs, which leave n units of the order.
+	fixed, fixedUnits := score{}, int64(0)
+	for i, c := range s.minimums {
+		fixed = s.add(fixed, i, c)
+		fixedUnits += int64(c) * int64(s.sizes[i])
+	}
+	n := max(0, ceilDiv(orderSize, unit)-fixedUnits)
+	maxPack := int64(s.sizes[len(s.sizes)-1])
+
+	// Without costs the best packing of a total is the one with the fewest
+	// packs, and that never has maxPack or more smaller packs: some of them
+	// always add up to whole largest packs, which ship the same total in
+	// fewer. When the largest size is unlimited, every total from a largest
+	// pack below the order up to one above it is thus best packed with all
+	// but (maxPack-1)² of its units in largest packs, so those are taken up
+	// front like the minimums and the DP only spans the rest.
+	var prefilled int64
+	if s.costs == nil && s.limits[len(s.limits)-1] < 0 {
+		if keep := maxPack + (maxPack-1)*(maxPack-1); n > keep {
+			prefilled = (n - keep) / maxPack
+			fixedUnits += prefilled * maxPack
+			n -= prefilled * maxPack
+		}
+	}
+	// maxTotal only filters the totals, so that the nearest ones can still
+	// be reported when none fits under it.
+	bound := saturatingAdd(n, maxPack-1)
+
+	// A size never needs more packs than fit below the bound.
+	clamped := make([]int64, len(s.sizes))
+	available, limitedOnly := int64(0), true
+	for i, size := range s.sizes {
+		clamped[i] = bound / int64(size)
+		if s.limits[i] < 0 {
+			limitedOnly = false
+			continue
+		}
+		limit := int64(s.limits[i])
+		if s.minimums != nil {
+			limit -= int64(s.minimums[i])
+		}
+		clamped[i] = min(clamped[i], limit)
+		available = saturatingAdd(available, clamped[i]*int64(size))
+	}
+
+	if limitedOnly {
+		if available < n {
+			stock := make(map[int]int64, len(sizes))
+			for i, size := range sizes {
+				stock[size] = int64(s.limits[i])
+			}
+			// Fewer units than the order's ceiling hold fewer items than it.
+			held := (fixedUnits + available) * unit
+			return nil, 0, &domain.InsufficientStockError{
+				OrderSize: orderSize,
+				Available: held,
+				Shortfall: orderSize - held,
+				Packs:     toPackResults(stock),
+			}
+		}
+		bound = min(bound, available)
+	}
+	if bound >= maxBoundedEntries/int64(len(s.sizes)) {
+		return nil, 0, fmt.Errorf("%w: the search would span %d amounts, at most %d fit",
+			domain.ErrOrderTooLarge, bound+1, maxBoundedEntries/int64(len(s.sizes)))
+	}
+	// Past the cap every amount fits in an int.
+	width, order := int(bound)+1, int(n)
+	limits := make([]int, len(clamped))
+	for i, c := range clamped {
+		limits[i] = int(c)
+	}
+
+	layers, err := s.fillLayers(ctx, limits, width)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	last := layers[len(layers)-1]
+	best := Candidate{Total: -1}
+	for x := order; x < width && (s.maxTotal == 0 || (fixedUnits+int64(x))*unit <= s.maxTotal); x++ {
+		at := last.at(x)
+		if at.packs < 0 {
+			continue
+		}
+		c := Candidate{
+			Total: (fixedUnits + int64(x)) * unit,
+			Packs: int64(fixed.packs) + prefilled + int64(at.packs),
+			Cost:  fixed.cost + at.cost,
+		}
+		if best.Total < 0 || s.policy.Less(orderSize, c, best) {
+			best = c
+		}
+	}
+	if best.Total < 0 {
+		return nil, 0, s.overshootError(orderSize, order, last, unit, fixedUnits)
+	}
+	total := int(best.Total/unit - fixedUnits)
+
+	// Walk the layers back from the largest size, taking as many packs of
+	// each as still lead to the optimum.
+	counts := make(map[int]int64)
+	for i, c := range s.minimums {
+		if c > 0 {
+			counts[sizes[i]] = int64(c)
+		}
+	}
+	if prefilled > 0 {
+		counts[sizes[len(sizes)-1]] += prefilled
+	}
+	for i, x := len(s.sizes)-1, total; i >= 0; i-- {
+		size := s.sizes[i]
+		for c := min(limits[i], x/size); c >= 0; c-- {
+			if p := s.previous(layers, i, x-c*size); p.packs >= 0 && s.add(p, i, c) == layers[i].at(x) {
+				if c > 0 {
+					counts[sizes[i]] += int64(c)
+				}
+				x -= c * size
+				break
+			}
+		}
+	}
+
+	return counts, best.Total, nil
+}
+
+// overshootError reports the shippable totals closest to the order when none
+// fits under maxTotal; n is what the fixed units leave of the order. Some
+// total at or above the order is always in the layer: the stock covers it,
+// and then some subset lies below the bound.
+func (s boundedSearch) overshootError(orderSize int64, n int, last boundedLayer, unit, fixedUnits int64) error {
+	err := &domain.OvershootError{OrderSize: orderSize, MaxOvershoot: s.maxTotal - orderSize}
+	for x := n - 1; x >= 0 && fixedUnits+int64(x) > 0; x-- {
+		if last.packs[x] >= 0 {
+			err.NearestBelow = (fixedUnits + int64(x)) * unit
+			break
+		}
+	}
+	for x := n; x < len(last.packs); x++ {
+		if last.packs[x] >= 0 {
+			err.NearestAbove = (fixedUnits + int64(x)) * unit
+			break
+		}
+	}
+	return err
+}
+
+// fillLayers computes, for every size i and amount x below width, the best
+// score of packings of sizes[0..i] making exactly x with at most limits[j]
+// packs of sizes[j].
+//
+// Within a residue class r of size, amount r+q*size takes c packs of size on
+// top of amount r+(q-c)*size of the previous layer. Scoring those packs
+// relative to q, the layer value is min(prev[r+p*size] - p packs) + q packs
+// over p in [q-limit, q]: a sliding-window minimum kept in a monotone queue.
+func (s boundedSearch) fillLayers(ctx context.Context, limits []int, width int) ([]boundedLayer, error) {
+	layers := make([]boundedLayer, len(s.sizes))
+	queue := make([]int, 0, width/s.sizes[0]+1)
+	steps := 0
+
+	for i, size := range s.sizes {
+		cur := boundedLayer{packs: make([]int32, width)}
+		if s.costs != nil {
+			cur.cost = make([]int64, width)
+		}
+
+		for r := 0; r < size && r < width; r++ {
+			queue = queue[:0]
+			head := 0
+			value := func(p int) score { return s.add(s.previous(layers, i, r+p*size), i, -p) }
+
+			for q, x := 0, r; x < width; q, x = q+1, x+size {
+				steps++
+				if steps%cancelCheckInterval == 0 {
+					if err := contextError(ctx); err != nil {
+						return nil, err
+					}
+				}
+
+				if s.previous(layers, i, x).packs >= 0 {
+					v := value(q)
+					for len(queue) > head && !value(queue[len(queue)-1]).less(v) {
+						queue = queue[:len(queue)-1]
+					}
+					queue = append(queue, q)
+				}
+				for len(queue) > head && queue[head] < q-limits[i] {
+					head++
+				}
+
+				if len(queue) > head {
+					cur.set(x, s.add(value(queue[head]), i, q))
+				} else {
+					cur.set(x, score{packs: -1})
+				}
+			}
+		}
+		layers[i] = cur
+	}
+	return layers, nil
+}
+
+// previous returns the score of amount x before size i is added; before the
+// first size only the empty packing exists.
+func (s boundedSearch) previous(layers []boundedLayer, i, x int) score {
+	if i > 0 {
+		return layers[i-1].at(x)
+	}
+	if x == 0 {
+		return score{}
+	}
+	return score{packs: -1}
+}
+
+// add returns sc with n packs of size i added, or removed for negative n.
+func (s boundedSearch) add(sc score, i, n int) score {
+	sc.packs += int32(n)
+	if s.costs != nil {
+		sc.cost += int64(n) * s.costs[i]
+	}
+	return sc
+}
@@ -0,0 +1,332 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveBoundedOptimum tries every combination of counts within the minimums,
+// the limits and the cap and returns the best total, its cost and pack count under the
+// search's policy, or a total of -1 if nothing fits.
+func naiveBoundedOptimum(orderSize int64, s boundedSearch) (total, cost, packs int64) {
+	total = -1
+	var walk func(i int, items, c, n int64)
+	walk = func(i int, items, c, n int64) {
+		if i == len(s.sizes) {
+			if items < orderSize || s.maxTotal > 0 && items > s.maxTotal {
+				return
+			}
+			candidate := Candidate{Total: items, Packs: n, Cost: c}
+			if total < 0 || s.policy.Less(orderSize, candidate, Candidate{Total: total, Packs: packs, Cost: cost}) {
+				total, cost, packs = items, c, n
+			}
+			return
+		}
+		size := int64(s.sizes[i])
+		lo := int64(0)
+		if s.minimums != nil {
+			lo = int64(s.minimums[i])
+		}
+		for k := lo; (s.limits[i] < 0 || k <= int64(s.limits[i])) && (k == lo || items+(k-1)*size < orderSize+int64(s.sizes[len(s.sizes)-1])); k++ {
+			var kc int64
+			if s.costs != nil {
+				kc = k * s.costs[i]
+			}
+			walk(i+1, items+k*size, c+kc, n+k)
+		}
+	}
+	walk(0, 0, 0, 0)
+	return total, cost, packs
+}
+
+func randomSearch(rng *rand.Rand) boundedSearch {
+	sizes := make([]int, 1+rng.Intn(4))
+	for j := range sizes {
+		sizes[j] = 1 + rng.Intn(40)
+	}
+	s := boundedSearch{sizes: slices.Compact(slices.Sorted(slices.Values(sizes)))}
+	s.limits = make([]int, len(s.sizes))
+	for j := range s.limits {
+		s.limits[j] = rng.Intn(7) - 1
+	}
+	s.policy = []Policy{FewestItems{}, FewestPacks{}, BoundedOvershoot{Percent: rng.Intn(30)}}[rng.Intn(3)]
+	if rng.Intn(2) == 0 {
+		s.policy = LowestCost{}
+		s.costs = make([]int64, len(s.sizes))
+		for j := range s.costs {
+			s.costs[j] = 1 + rng.Int63n(50)
+		}
+	}
+	return s
+}
+
+func TestBoundedSearch_MatchesNaiveSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		s := randomSearch(rng)
+
+		for orderSize := int64(1); orderSize <= 150; orderSize += 1 + rng.Int63n(5) {
+			s.maxTotal = 0
+			if rng.Intn(3) == 0 {
+				s.maxTotal = orderSize + rng.Int63n(10)
+			}
+
+			wantTotal, wantCost, wantPacks := naiveBoundedOptimum(orderSize, s)
+			counts, total, err := s.compose(context.Background(), orderSize)
+			if wantTotal < 0 {
+				require.Error(t, err, "search %+v, order %d", s, orderSize)
+				continue
+			}
+			require.NoError(t, err, "search %+v, order %d", s, orderSize)
+
+			gotTotal, gotPacks := sumPacks(counts)
+			var gotCost int64
+			if s.costs != nil {
+				for j, size := range s.sizes {
+					gotCost += counts[size] * s.costs[j]
+				}
+			}
+			require.Equal(t, wantTotal, total, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantTotal, gotTotal, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantCost, gotCost, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "search %+v, order %d", s, orderSize)
+			require.True(t, withinLimits(counts, s.sizes, nil, s.limits))
+		}
+	}
+}
+
+func TestBoundedSearch_Minimums(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 200; i++ {
+		s := randomSearch(rng)
+		s.minimums = make([]int, len(s.sizes))
+		for j, limit := range s.limits {
+			s.minimums[j] = rng.Intn(3)
+			if limit >= 0 {
+				s.minimums[j] = min(s.minimums[j], limit)
+			}
+		}
+
+		for orderSize := int64(1); orderSize <= 120; orderSize += 1 + rng.Int63n(7) {
+			wantTotal, wantCost, wantPacks := naiveBoundedOptimum(orderSize, s)
+			counts, total, err := s.compose(context.Background(), orderSize)
+			if wantTotal < 0 {
+				require.Error(t, err, "search %+v, order %d", s, orderSize)
+				continue
+			}
+			require.NoError(t, err, "search %+v, order %d", s, orderSize)
+
+			gotTotal, gotPacks := sumPacks(counts)
+			require.Equal(t, wantTotal, total, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantTotal, gotTotal, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "search %+v, order %d", s, orderSize)
+			if s.costs != nil {
+				var gotCost int64
+				for j, size := range s.sizes {
+					gotCost += counts[size] * s.costs[j]
+				}
+				require.Equal(t, wantCost, gotCost, "search %+v, order %d", s, orderSize)
+			}
+			require.True(t, withinLimits(counts, s.sizes, s.minimums, s.limits))
+		}
+	}
+}
+
+func TestBoundedSearch_UnlimitedSizes(t *testing.T) {
+	sizes := []int{250, 500, 1000, 2000, 5000}
+
+	// Without 5000s, 12001 needs six 2000s and a 250.
+	counts, total, err := boundedSearch{sizes: sizes, limits: []int{-1, -1, -1, -1, 0}, policy: FewestItems{}}.compose(context.Background(), 12001)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{2000: 6, 250: 1}, counts)
+	assert.Equal(t, int64(12250), total)
+
+	// Unlimited sizes match the unconstrained solver.
+	table := mustPackTable(t, sizes...)
+	for orderSize := int64(1); orderSize <= 20000; orderSize += 137 {
+		counts, _, err := boundedSearch{sizes: sizes, limits: unlimited(sizes), policy: FewestItems{}}.compose(context.Background(), orderSize)
+		require.NoError(t, err)
+		wantTotal, wantPacks := sumPacks(mustOptimalPacks(t, orderSize, table))
+		gotTotal, gotPacks := sumPacks(counts)
+		require.Equal(t, wantTotal, gotTotal, "order %d", orderSize)
+		require.Equal(t, wantPacks, gotPacks, "order %d", orderSize)
+	}
+}
+
+func TestBoundedSearch_Costs(t *testing.T) {
+	// Two 250s cost less than one 500, so 501 ships as three 250s rather
+	// than a 500 and a 250 or a 1000.
+	s := boundedSearch{sizes: []int{250, 500, 1000}, limits: unlimited([]int{250, 500, 1000}), costs: []int64{40, 100, 170}, policy: LowestCost{}}
+
+	counts, total, err := s.compose(context.Background(), 501)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{250: 3}, counts)
+	assert.Equal(t, int64(750), total)
+
+	// Four 250s (160) beat a single 1000 (170) despite the extra packs.
+	counts, _, err = s.compose(context.Background(), 1000)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{250: 4}, counts)
+
+	// On equal cost the fewer items win: 500 (80) over 750 (80).
+	s.costs = []int64{40, 80, 170}
+	counts, total, err = s.compose(context.Background(), 260)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{500: 1}, counts)
+	assert.Equal(t, int64(500), total)
+}
+
+func TestBoundedSearch_InsufficientStock(t *testing.T) {
+	sizes := []int{250, 500, 1000}
+	_, _, err := boundedSearch{sizes: sizes, limits: []int{2, 1, 0}, policy: FewestItems{}}.compose(context.Background(), 2000)
+
+	var stockErr *domain.InsufficientStockError
+	require.ErrorAs(t, err, &stockErr)
+	assert.Equal(t, &domain.InsufficientStockError{
+		OrderSize: 2000,
+		Available: 1000,
+		Shortfall: 1000,
+		Packs:     []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 2}},
+	}, stockErr)
+}
+
+func TestBoundedSearch_OvershootLimit(t *testing.T) {
+	sizes := []int{250, 500}
+	s := boundedSearch{sizes: sizes, limits: unlimited(sizes), costs: []int64{10, 10}, policy: LowestCost{}, maxTotal: 260}
+
+	_, _, err := s.compose(context.Background(), 251)
+	var overshootErr *domain.OvershootError
+	require.ErrorAs(t, err, &overshootErr)
+	assert.Equal(t, &domain.OvershootError{OrderSize: 251, MaxOvershoot: 9, NearestBelow: 250, NearestAbove: 500}, overshootErr)
+
+	s.maxTotal = 500
+	counts, _, err := s.compose(context.Background(), 251)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{500: 1}, counts)
+}
+
+func TestBoundedSearch_LargestPacksUpFront(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	// Small sizes put most of these orders past the units kept for the DP.
+	for i := 0; i < 150; i++ {
+		s := randomSearch(rng)
+		for j := range s.sizes {
+			s.sizes[j] = 4 + s.sizes[j]%7
+		}
+		s.sizes = slices.Compact(slices.Sorted(slices.Values(s.sizes)))
+		s.limits = s.limits[:len(s.sizes)]
+		s.limits[len(s.limits)-1] = -1
+		s.policy = []Policy{FewestItems{}, FewestPacks{}, LargerPacks{}, BoundedOvershoot{Percent: rng.Intn(30)}}[rng.Intn(4)]
+		s.costs = nil
+
+		for orderSize := int64(100); orderSize <= 240; orderSize += 1 + rng.Int63n(9) {
+			wantTotal, _, wantPacks := naiveBoundedOptimum(orderSize, s)
+			counts, total, err := s.compose(context.Background(), orderSize)
+			require.NoError(t, err, "search %+v, order %d", s, orderSize)
+
+			gotTotal, gotPacks := sumPacks(counts)
+			require.Equal(t, wantTotal, total, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantTotal, gotTotal, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "search %+v, order %d", s, orderSize)
+			require.True(t, withinLimits(counts, s.sizes, nil, s.limits))
+		}
+	}
+
+	// Far past the DP's cap, the fewest packs still come back: 188680 packs
+	// are needed, and one 23 in place of a 53 comes closest to the order.
+	sizes := []int{23, 31, 53}
+	counts, total, err := boundedSearch{sizes: sizes, limits: unlimited(sizes), policy: FewestPacks{}}.compose(context.Background(), 10_000_000)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{53: 188_679, 23: 1}, counts)
+	assert.Equal(t, int64(10_000_010), total)
+}
+
+func TestBoundedSearch_OrderTooLarge(t *testing.T) {
+	_, _, err := boundedSearch{sizes: []int{23, 31}, limits: []int{-1, 3}, policy: FewestItems{}}.compose(context.Background(), 100_000_000)
+	assert.ErrorIs(t, err, domain.ErrOrderTooLarge)
+
+	// Counted in units of 250, the same order fits.
+	counts, total, err := boundedSearch{sizes: []int{250, 500}, limits: []int{-1, 3}, policy: FewestItems{}}.compose(context.Background(), 100_000_000)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{500: 3, 250: 399_994}, counts)
+	assert.Equal(t, int64(100_000_000), total)
+}
+
+func TestBoundedSearch_CommonDivisor(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		s := randomSearch(rng)
+		scale := 2 + rng.Intn(4)
+		for j := range s.sizes {
+			s.sizes[j] *= scale
+		}
+
+		for orderSize := int64(1); orderSize <= 300; orderSize += 1 + rng.Int63n(9) {
+			s.maxTotal = 0
+			if rng.Intn(3) == 0 {
+				s.maxTotal = orderSize + rng.Int63n(20)
+			}
+
+			wantTotal, _, wantPacks := naiveBoundedOptimum(orderSize, s)
+			counts, total, err := s.compose(context.Background(), orderSize)
+			if wantTotal < 0 {
+				require.Error(t, err, "search %+v, order %d", s, orderSize)
+				continue
+			}
+			require.NoError(t, err, "search %+v, order %d", s, orderSize)
+
+			gotTotal, gotPacks := sumPacks(counts)
+			require.Equal(t, wantTotal, total, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantTotal, gotTotal, "search %+v, order %d", s, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "search %+v, order %d", s, orderSize)
+		}
+	}
+}
+
+func TestStockLimits(t *testing.T) {
+	stock := []domain.PackStock{{Size: 500, Available: 3}, {Size: 750, Available: 1}, {Size: 250, Available: 0}}
+	assert.Equal(t, []int{0, 3, -1}, stockLimits([]int{250, 500, 1000}, stock))
+}
+
+func TestConstraintBounds(t *testing.T) {
+	two := 2
+	constraints := []domain.PackConstraint{
+		{Size: 250, Max: &two},
+		{Size: 1000, Min: 1, OrdersOver: 5000},
+		{Size: 750, Min: 4},
+	}
+
+	mins, maxes, ok := constraintBounds([]int{250, 500, 1000}, constraints, 5000)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 0, 0}, mins)
+	assert.Equal(t, []int{2, -1, -1}, maxes)
+
+	mins, maxes, ok = constraintBounds([]int{250, 500, 1000}, constraints, 5001)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 0, 1}, mins)
+	assert.Equal(t, []int{2, -1, -1}, maxes)
+
+	_, _, ok = constraintBounds([]int{500}, constraints, 5001)
+	assert.False(t, ok)
+}
+
+func TestPackCosts(t *testing.T) {
+	costs := []domain.PackCost{{Size: 500, Cost: 90}, {Size: 250, Cost: 50}, {Size: 750, Cost: 1}}
+
+	got, err := packCosts([]int{250, 500}, costs)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{50, 90}, got)
+
+	_, err = packCosts([]int{250, 500, 1000}, costs)
+	assert.ErrorIs(t, err, domain.ErrMissingPackCosts)
+}
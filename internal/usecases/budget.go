@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// cancelCheckInterval is how many loop iterations the solver runs between
+// context checks; checking on every step would dominate the inner loops.
+const cancelCheckInterval = 1 << 16
+
+// withComputeBudget derives a context that expires with
+// domain.ErrComputeBudgetExceeded as its cause once the budget is spent.
+func withComputeBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, budget, domain.ErrComputeBudgetExceeded)
+}
+
+// contextError reports why ctx is done, telling an exhausted compute budget
+// apart from the caller going away. It returns nil while ctx is live.
+func contextError(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	if errors.Is(context.Cause(ctx), domain.ErrComputeBudgetExceeded) {
+		return domain.ErrComputeBudgetExceeded
+	}
+	return fmt.Errorf("%w: %w", domain.ErrCalculationCanceled, ctx.Err())
+}
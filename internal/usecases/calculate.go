@@ -2,153 +2,493 @@ package usecases
 
 import (
 	"calculate_product_packs/internal/domain"
-	"math"
+	"context"
+	"fmt"
+	"maps"
+	"runtime"
 	"sort"
+	"time"
 )
 
 type CalculatePacksUseCase struct {
-	repo domain.PackSizeRepository
+	repo          domain.PackSizeRepository
+	tables        *PackTableCache
+	computeBudget time.Duration
+	policy        Policy
+	history       domain.OrderHistoryRepository
+	batchWorkers  int
+	results       *ResultCache
+	flights       *flightGroup
+	shadow        *shadowSolver
 }
 
-func NewCalculatePacksUseCase(repo domain.PackSizeRepository) *CalculatePacksUseCase {
-	return &CalculatePacksUseCase{repo: repo}
+type CalculateOption func(*CalculatePacksUseCase)
+
+// WithComputeBudget limits how long a single calculation may run, including
+// building the tables for a new pack set. Zero means no limit.
+func WithComputeBudget(d time.Duration) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.computeBudget = d
+	}
 }
 
-func (uc *CalculatePacksUseCase) Execute(orderSize int) ([]domain.PackResult, error) {
-	if orderSize <= 0 {
-		return nil, domain.ErrOrderSizePositive
+// WithDefaultPolicy sets the policy for calculations that do not name one.
+// Without it the default is FewestItems.
+func WithDefaultPolicy(p Policy) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.policy = p
 	}
+}
 
-	packSizes := uc.repo.GetPackSizes()
-	if len(packSizes) == 0 {
-		return nil, domain.ErrNoPackSizes
+// WithOrderHistory records the size of every order Execute or Explain packs.
+func WithOrderHistory(h domain.OrderHistoryRepository) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.history = h
 	}
+}
 
-	sizes := make([]int, len(packSizes))
-	for i, ps := range packSizes {
-		sizes[i] = int(ps)
+// WithBatchWorkers sets how many orders of a batch are packed at once.
+// Without it batches use one worker per CPU.
+func WithBatchWorkers(n int) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.batchWorkers = n
 	}
+}
 
-	result := calculateOptimalPacks(orderSize, sizes)
+// WithResultCache serves repeated Execute calls from the cache while the
+// pack-set version holds.
+func WithResultCache(c *ResultCache) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.results = c
+	}
+}
 
-	var packResults []domain.PackResult
-	for size, count := range result {
-		if count > 0 {
-			packResults = append(packResults, domain.PackResult{Size: domain.PackSize(size), Count: count})
+// WithCoalescing lets concurrent Execute calls for the same order, options
+// and pack-set version share one calculation.
+func WithCoalescing() CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.flights = newFlightGroup()
+	}
+}
+
+// WithShadowSolver re-checks percent percent of the packings Execute
+// calculates against VerifyPacking in the background, for policies that rank
+// by items, then packs, without stock or pack constraints. Responses never
+// wait for the check; Stats reports the outcome.
+func WithShadowSolver(percent float64) CalculateOption {
+	return func(uc *CalculatePacksUseCase) {
+		uc.shadow = newShadowSolver(percent)
+	}
+}
+
+func NewCalculatePacksUseCase(
+	repo domain.PackSizeRepository,
+	tables *PackTableCache,
+	opts ...CalculateOption,
+) *CalculatePacksUseCase {
+	uc := &CalculatePacksUseCase{repo: repo, tables: tables, policy: FewestItems{}, batchWorkers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+func (uc *CalculatePacksUseCase) Execute(
+	ctx context.Context,
+	orderSize int64,
+	opts domain.CalculateOptions,
+) ([]domain.PackResult, error) {
+	policy, err := uc.resolvePolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.computeBudget)
+	defer cancel()
+
+	calculate := func(ctx context.Context) ([]domain.PackResult, error) {
+		return uc.calculate(ctx, orderSize, policy, opts)
+	}
+
+	var packs []domain.PackResult
+	if (uc.results != nil || uc.flights != nil) && orderSize > 0 {
+		// The version is read before the pack set, so a packing is never
+		// stored under a newer version than the one it was computed from.
+		key := newResultKey(uc.repo.PackSetVersion(), orderSize, policy, opts)
+		packs, err = uc.calculateShared(ctx, key, calculate)
+	} else {
+		packs, err = calculate(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.record(orderSize)
+
+	return packs, nil
+}
+
+// calculateShared serves the packing for key from the result cache, or from a
+// calculation already running for it, before running calculate itself.
+func (uc *CalculatePacksUseCase) calculateShared(
+	ctx context.Context,
+	key resultKey,
+	calculate func(ctx context.Context) ([]domain.PackResult, error),
+) ([]domain.PackResult, error) {
+	if uc.results != nil {
+		if packs, ok := uc.results.get(key); ok {
+			return packs, nil
+		}
+		calculateUncached := calculate
+		calculate = func(ctx context.Context) ([]domain.PackResult, error) {
+			packs, err := calculateUncached(ctx)
+			if err == nil {
+				uc.results.put(key, packs)
+			}
+			return packs, err
 		}
 	}
+	if uc.flights != nil {
+		return uc.flights.do(ctx, key, uc.computeBudget, calculate)
+	}
+	return calculate(ctx)
+}
 
-	sort.Slice(packResults, func(i, j int) bool {
-		return packResults[i].Size > packResults[j].Size
-	})
+// calculate packs the order under the current pack set.
+func (uc *CalculatePacksUseCase) calculate(
+	ctx context.Context,
+	orderSize int64,
+	policy Policy,
+	opts domain.CalculateOptions,
+) ([]domain.PackResult, error) {
+	table, err := uc.table(ctx, orderSize)
+	if err != nil {
+		return nil, err
+	}
 
-	return packResults, nil
+	sol, err := uc.solve(ctx, orderSize, uc.state(table, policy, opts), policy, opts)
+	if err != nil {
+		return nil, err
+	}
+	packs := toPackResults(sol.counts)
+	if uc.shadow != nil && itemsFirst(policy) && sol.limits == nil {
+		uc.shadow.observe(ctx, orderSize, table.sizes, packs)
+	}
+	return packs, nil
 }
 
-// calculateOptimalPacks finds the optimal pack combination for the given order.
-//
-// Rules (in priority order):
-//  1. Only whole packs can be sent
-//  2. Minimize total items sent (must be >= orderSize)
-//  3. Among solutions with equal total items, minimize number of packs
-//
-// Uses dynamic programming (variant of coin change problem). For large orders,
-// pre-allocates largest packs to keep the DP table size manageable.
-func calculateOptimalPacks(orderSize int, packSizes []int) map[int]int {
-	sort.Ints(packSizes)
+// Close waits for the background checks of WithShadowSolver to finish and
+// starts no more. Calculations keep working after it.
+func (uc *CalculatePacksUseCase) Close() {
+	if uc.shadow != nil {
+		uc.shadow.close()
+	}
+}
+
+// Stats reports on how calculations were served.
+func (uc *CalculatePacksUseCase) Stats() domain.CalculationStats {
+	var stats domain.CalculationStats
+	if uc.results != nil {
+		stats.ResultCache = uc.results.Stats()
+	}
+	if uc.flights != nil {
+		stats.Coalescing = uc.flights.stats()
+	}
+	if uc.shadow != nil {
+		stats.Shadow = uc.shadow.stats()
+	}
+	if uc.repo != nil {
+		stats.PackSetVersion = uc.repo.PackSetVersion()
+	}
+	return stats
+}
+
+// Explain calculates the packing like Execute and reports the totals, the rule
+// that decided it and how it was computed.
+func (uc *CalculatePacksUseCase) Explain(
+	ctx context.Context,
+	orderSize int64,
+	opts domain.CalculateOptions,
+) (*domain.Calculation, error) {
+	policy, err := uc.resolvePolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.computeBudget)
+	defer cancel()
 
-	minPack := packSizes[0]
-	maxPack := packSizes[len(packSizes)-1]
+	// Read before the pack set, like the result cache key.
+	version := uc.repo.PackSetVersion()
+	table, err := uc.table(ctx, orderSize)
+	if err != nil {
+		return nil, err
+	}
 
-	// For large orders, pre-subtract largest packs to keep the DP table small.
-	dpLimit := minPack * maxPack
-	if dpLimit < maxPack+minPack {
-		dpLimit = maxPack + minPack
+	sol, err := uc.solve(ctx, orderSize, uc.state(table, policy, opts), policy, opts)
+	if err != nil {
+		return nil, err
 	}
+	uc.record(orderSize)
+
+	packCount := sumCounts(sol.counts)
 
-	baseLargePacks := 0
-	effOrder := orderSize
-	if effOrder > dpLimit {
-		baseLargePacks = (effOrder - dpLimit) / maxPack
-		effOrder = orderSize - baseLargePacks*maxPack
+	// The first other packing of the same total, in pack count order, tells
+	// which rule ruled it out. Packings the stock or the constraints rule out
+	// do not count.
+	decidedBy := policy.Name()
+	if itemsFirst(policy) {
+		decidedBy = domain.RuleFewestItems
+		err = enumeratePackings(ctx, table.sizes, sol.total, packCount, func(other map[int]int64) bool {
+			if maps.Equal(other, sol.counts) || !withinLimits(other, table.sizes, sol.minimums, sol.limits) {
+				return true
+			}
+			decidedBy = domain.RuleFewestPacks
+			if sumCounts(other) == packCount {
+				decidedBy = domain.RuleTieBreak
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	maxTarget := effOrder + minPack - 1
+	return &domain.Calculation{
+		Packs:              toPackResults(sol.counts),
+		TotalItems:         sol.total,
+		Overshoot:          sol.total - orderSize,
+		PackCount:          packCount,
+		Cost:               sol.cost,
+		Policy:             policy.Name(),
+		DecidedBy:          decidedBy,
+		LargeOrderShortcut: sol.largeOrder,
+		PackSetVersion:     version,
+	}, nil
+}
 
-	const inf = math.MaxInt32
-	dp := make([]int, maxTarget+1)
-	from := make([]int, maxTarget+1)
-	for i := range dp {
-		dp[i] = inf
+// packState is what solve reads besides the order: the table of the pack set
+// and the settings of its sizes. Reading it once lets many orders see the
+// same pack set.
+type packState struct {
+	table       *packTable
+	constraints []domain.PackConstraint
+	stock       []domain.PackStock // only with RespectStock
+	costs       []domain.PackCost  // only for policies that use costs
+}
+
+// state reads the settings solve needs under the policy and opts.
+func (uc *CalculatePacksUseCase) state(table *packTable, policy Policy, opts domain.CalculateOptions) *packState {
+	st := &packState{table: table, constraints: uc.repo.GetConstraints()}
+	if opts.RespectStock {
+		st.stock = uc.repo.GetStock()
+	}
+	if usesCosts(policy) {
+		st.costs = uc.repo.GetCosts()
 	}
-	dp[0] = 0
+	return st
+}
+
+// solution is a packing for an order and how it was found.
+type solution struct {
+	counts     map[int]int64
+	total      int64
+	cost       int64 // summed pack cost for CostAware policies, else 0
+	largeOrder bool
+	// limits and minimums are the pack counts per size the packing keeps to,
+	// nil when neither stock nor constraints were consulted.
+	limits   []int
+	minimums []int
+}
+
+// solve packs the order under the policy, opts and the pack constraints. The
+// residue tables answer FewestItems without stock limits or constraints;
+// everything else needs a bounded search.
+func (uc *CalculatePacksUseCase) solve(
+	ctx context.Context,
+	orderSize int64,
+	st *packState,
+	policy Policy,
+	opts domain.CalculateOptions,
+) (*solution, error) {
+	table := st.table
+	maxOvershoot, capped := overshootLimit(orderSize, opts)
+	mins, maxes, constrained := constraintBounds(table.sizes, st.constraints, orderSize)
 
-	for i := 1; i <= maxTarget; i++ {
-		for _, pack := range packSizes {
-			if pack > i {
-				break
+	if _, ok := policy.(FewestItems); ok && !opts.RespectStock && !constrained {
+		total, ok := table.smallestTotal(orderSize)
+		if !ok {
+			return nil, domain.ErrTotalOverflow
+		}
+		if capped && total-orderSize > maxOvershoot {
+			return nil, &domain.OvershootError{
+				OrderSize:    orderSize,
+				MaxOvershoot: maxOvershoot,
+				NearestBelow: table.largestTotalBelow(orderSize),
+				NearestAbove: total,
 			}
-			if dp[i-pack] < inf && dp[i-pack]+1 < dp[i] {
-				dp[i] = dp[i-pack] + 1
-				from[i] = pack
+		}
+		counts, largeOrder, err := table.compose(ctx, total)
+		if err != nil {
+			return nil, err
+		}
+		return &solution{counts: counts, total: total, largeOrder: largeOrder}, nil
+	}
+
+	search := boundedSearch{sizes: table.sizes, limits: unlimited(table.sizes), policy: policy}
+	if opts.RespectStock {
+		search.limits = stockLimits(table.sizes, st.stock)
+	}
+	if constrained {
+		if err := checkConstraints(orderSize, table.sizes, mins, maxes, search.limits); err != nil {
+			return nil, err
+		}
+		for i, limit := range maxes {
+			if limit >= 0 && (search.limits[i] < 0 || limit < search.limits[i]) {
+				search.limits[i] = limit
 			}
 		}
+		search.minimums = mins
+	}
+	if usesCosts(policy) {
+		costs, err := packCosts(table.sizes, st.costs)
+		if err != nil {
+			return nil, err
+		}
+		search.costs = costs
+	}
+	if capped {
+		// A cap at or above the largest pack never binds.
+		search.maxTotal = saturatingAdd(orderSize, min(maxOvershoot, int64(table.sizes[len(table.sizes)-1])))
+	}
+
+	counts, total, err := search.compose(ctx, orderSize)
+	if err != nil {
+		return nil, err
 	}
 
-	type solution struct {
-		dpAmount   int
-		largePacks int
-		totalItems int
-		totalPacks int
+	sol := &solution{counts: counts, total: total}
+	if opts.RespectStock || constrained {
+		sol.limits, sol.minimums = search.limits, search.minimums
 	}
-	var best *solution
+	if search.costs != nil {
+		for i, size := range search.sizes {
+			sol.cost += counts[size] * search.costs[i]
+		}
+	}
+	return sol, nil
+}
 
-	maxExtra := effOrder / maxPack
-	for extra := 0; extra <= maxExtra; extra++ {
-		remainder := effOrder - extra*maxPack
-		if remainder < 0 {
-			break
+// checkConstraints rules out an order whose constraints no packing meets: a
+// minimum the stock cannot cover, or maxima on every size that hold fewer
+// items than the order. stock is -1 for sizes it does not limit.
+func checkConstraints(orderSize int64, sizes, mins, maxes, stock []int) error {
+	capacity, capped := int64(0), true
+	for i, size := range sizes {
+		if stock[i] >= 0 && mins[i] > stock[i] {
+			return fmt.Errorf("%w: %d packs of %d required, %d in stock", domain.ErrPackConstraintsUnmet, mins[i], size, stock[i])
 		}
-		searchEnd := remainder + minPack - 1
-		if searchEnd > maxTarget {
+		if maxes[i] < 0 {
+			capped = false
 			continue
 		}
+		capacity += int64(maxes[i]) * int64(size)
+	}
+	if capped && capacity < orderSize {
+		return fmt.Errorf("%w: at most %d items fit", domain.ErrPackConstraintsUnmet, capacity)
+	}
+	return nil
+}
 
-		for t := remainder; t <= searchEnd; t++ {
-			if dp[t] < inf {
-				totalItems := (baseLargePacks+extra)*maxPack + t
-				totalPacks := baseLargePacks + extra + dp[t]
-
-				if best == nil ||
-					totalItems < best.totalItems ||
-					(totalItems == best.totalItems && totalPacks < best.totalPacks) {
-					best = &solution{
-						dpAmount:   t,
-						largePacks: baseLargePacks + extra,
-						totalItems: totalItems,
-						totalPacks: totalPacks,
-					}
-				}
-				break
-			}
+// overshootLimit returns the tightest overshoot cap opts set for the order,
+// and false if they set none.
+func overshootLimit(orderSize int64, opts domain.CalculateOptions) (int64, bool) {
+	limit, capped := int64(0), opts.ExactOnly
+	if opts.MaxOvershoot != nil && (!capped || *opts.MaxOvershoot < limit) {
+		limit, capped = *opts.MaxOvershoot, true
+	}
+	if opts.MaxOvershootPercent != nil {
+		if byPercent := percentOf(orderSize, *opts.MaxOvershootPercent); !capped || byPercent < limit {
+			limit, capped = byPercent, true
 		}
 	}
+	return limit, capped
+}
+
+// resolvePolicy validates opts and returns the policy they select.
+func (uc *CalculatePacksUseCase) resolvePolicy(opts domain.CalculateOptions) (Policy, error) {
+	if opts.MaxOvershoot != nil && *opts.MaxOvershoot < 0 ||
+		opts.MaxOvershootPercent != nil && (*opts.MaxOvershootPercent < 0 || *opts.MaxOvershootPercent > maxOvershootPercent) {
+		return nil, domain.ErrInvalidOvershoot
+	}
+	if opts.Policy == "" {
+		return uc.policy, nil
+	}
+	return ParsePolicy(opts.Policy)
+}
 
-	if best == nil {
-		count := (orderSize + minPack - 1) / minPack
-		return map[int]int{minPack: count}
+// table validates the order and returns the solver table for the current pack
+// set.
+func (uc *CalculatePacksUseCase) table(ctx context.Context, orderSize int64) (*packTable, error) {
+	if orderSize <= 0 {
+		return nil, domain.ErrOrderSizePositive
 	}
 
-	result := make(map[int]int)
-	if best.largePacks > 0 {
-		result[maxPack] = best.largePacks
+	packSizes := uc.repo.GetPackSizes()
+	if len(packSizes) == 0 {
+		return nil, domain.ErrNoPackSizes
 	}
-	remaining := best.dpAmount
-	for remaining > 0 {
-		pack := from[remaining]
-		result[pack]++
-		remaining -= pack
+
+	return uc.tables.get(ctx, packSizes)
+}
+
+func (uc *CalculatePacksUseCase) record(orderSize int64) {
+	if uc.history != nil {
+		uc.history.RecordOrder(orderSize)
+	}
+}
+
+// toPackResults converts pack counts keyed by size into results ordered from
+// the largest pack down.
+func toPackResults(counts map[int]int64) []domain.PackResult {
+	var packResults []domain.PackResult
+	for size, count := range counts {
+		if count > 0 {
+			packResults = append(packResults, domain.PackResult{Size: domain.PackSize(size), Count: count})
+		}
 	}
 
-	return result
+	sort.Slice(packResults, func(i, j int) bool {
+		return packResults[i].Size > packResults[j].Size
+	})
+
+	return packResults
+}
+
+func sumCounts(counts map[int]int64) int64 {
+	n := int64(0)
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
+
+// calculateOptimalPacks finds the optimal pack combination for the given order.
+//
+// Rules (in priority order):
+//  1. Only whole packs can be sent
+//  2. Minimize total items sent (must be >= orderSize)
+//  3. Among solutions with equal total items, minimize number of packs
+//
+// Rather than a DP over every amount up to minPack*maxPack, the table solves
+// shortest paths over residue classes (see packTable): classes modulo the
+// smallest pack give the fewest items, classes modulo the largest pack give
+// the fewest packs, with the largest packs pre-allocated for large orders.
+// A query costs O(minPack) to find the total plus the size of the result.
+func calculateOptimalPacks(ctx context.Context, orderSize int64, table *packTable) (map[int]int64, error) {
+	total, ok := table.smallestTotal(orderSize)
+	if !ok {
+		return nil, domain.ErrTotalOverflow
+	}
+	result, _, err := table.compose(ctx, total)
+	return result, err
 }
@@ -3,9 +3,13 @@ package usecases
 import (
 	"calculate_product_packs/internal/domain"
 	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -13,7 +17,7 @@ func TestCalculatePacksUseCase_Execute(t *testing.T) {
 	tests := []struct {
 		name          string
 		packSizes     []domain.PackSize
-		orderSize     int
+		orderSize     int64
 		expectedPacks []domain.PackResult
 		expectedError error
 	}{
@@ -97,9 +101,10 @@ func TestCalculatePacksUseCase_Execute(t *testing.T) {
 
 			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
 			mockRepo.EXPECT().GetPackSizes().Return(tt.packSizes)
+			mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
 
-			useCase := NewCalculatePacksUseCase(mockRepo)
-			result, err := useCase.Execute(tt.orderSize)
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Execute(context.Background(), tt.orderSize, domain.CalculateOptions{})
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -117,24 +122,49 @@ func TestCalculatePacksUseCase_Execute_SortsPackSizes(t *testing.T) {
 
 	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
 	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 1000, 500, 5000, 2000})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
 
-	useCase := NewCalculatePacksUseCase(mockRepo)
-	result, err := useCase.Execute(12001)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	result, err := useCase.Execute(context.Background(), 12001, domain.CalculateOptions{})
 
 	assert.NoError(t, err)
 	expected := []domain.PackResult{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}}
 	assert.Equal(t, expected, result)
 }
 
+func TestCalculatePacksUseCase_RecordsOrderHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500}).Times(3)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+	mockHistory := mocks.NewMockOrderHistoryRepository(ctrl)
+	mockHistory.EXPECT().RecordOrder(int64(501))
+	mockHistory.EXPECT().RecordOrder(int64(251))
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithOrderHistory(mockHistory))
+	_, err := useCase.Execute(context.Background(), 501, domain.CalculateOptions{})
+	require.NoError(t, err)
+	_, err = useCase.Explain(context.Background(), 251, domain.CalculateOptions{})
+	require.NoError(t, err)
+
+	// Failed calculations are not recorded.
+	_, err = useCase.Execute(context.Background(), 251, domain.CalculateOptions{ExactOnly: true})
+	assert.Error(t, err)
+}
+
 func TestCalculatePacksUseCase_Execute_EmptyPackSizes(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
 	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
 
-	useCase := NewCalculatePacksUseCase(mockRepo)
-	result, err := useCase.Execute(1000)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	result, err := useCase.Execute(context.Background(), 1000, domain.CalculateOptions{})
 
 	assert.ErrorIs(t, err, domain.ErrNoPackSizes)
 	assert.Empty(t, result)
@@ -145,27 +175,501 @@ func TestCalculatePacksUseCase_Execute_InvalidInputs(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
-	useCase := NewCalculatePacksUseCase(mockRepo)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	result, err := useCase.Execute(context.Background(), -100, domain.CalculateOptions{})
+	assert.ErrorIs(t, err, domain.ErrOrderSizePositive)
+	assert.Empty(t, result)
 
-	result, err := useCase.Execute(-100)
+	result, err = useCase.Execute(context.Background(), 0, domain.CalculateOptions{})
 	assert.ErrorIs(t, err, domain.ErrOrderSizePositive)
 	assert.Empty(t, result)
+}
+
+func TestCalculatePacksUseCase_Explain(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []domain.PackSize
+		orderSize int64
+		expected  *domain.Calculation
+	}{
+		{
+			name:      "only packing of the smallest total",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 250,
+			expected: &domain.Calculation{
+				Packs:          []domain.PackResult{{Size: 250, Count: 1}},
+				TotalItems:     250,
+				PackCount:      1,
+				Policy:         "fewest-items",
+				DecidedBy:      domain.RuleFewestItems,
+				PackSetVersion: 1,
+			},
+		},
+		{
+			name:      "fewer packs than two 250s",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 251,
+			expected: &domain.Calculation{
+				Packs:          []domain.PackResult{{Size: 500, Count: 1}},
+				TotalItems:     500,
+				Overshoot:      249,
+				PackCount:      1,
+				Policy:         "fewest-items",
+				DecidedBy:      domain.RuleFewestPacks,
+				PackSetVersion: 1,
+			},
+		},
+		{
+			name:      "large order pre-allocates the largest packs",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 1000000,
+			expected: &domain.Calculation{
+				Packs:              []domain.PackResult{{Size: 5000, Count: 200}},
+				TotalItems:         1000000,
+				PackCount:          200,
+				Policy:             "fewest-items",
+				DecidedBy:          domain.RuleFewestPacks,
+				LargeOrderShortcut: true,
+				PackSetVersion:     1,
+			},
+		},
+		{
+			name:      "largest pack within the stored amounts is looked up",
+			packSizes: []domain.PackSize{250, 500, 1000, 2000, 5000},
+			orderSize: 5000,
+			expected: &domain.Calculation{
+				Packs:          []domain.PackResult{{Size: 5000, Count: 1}},
+				TotalItems:     5000,
+				PackCount:      1,
+				Policy:         "fewest-items",
+				DecidedBy:      domain.RuleFewestPacks,
+				PackSetVersion: 1,
+			},
+		},
+		{
+			name:      "same pack count decided by tie-break",
+			packSizes: []domain.PackSize{1, 2, 3},
+			orderSize: 4,
+			expected: &domain.Calculation{
+				Packs:          []domain.PackResult{{Size: 3, Count: 1}, {Size: 1, Count: 1}},
+				TotalItems:     4,
+				PackCount:      2,
+				Policy:         "fewest-items",
+				DecidedBy:      domain.RuleTieBreak,
+				PackSetVersion: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			mockRepo.EXPECT().GetPackSizes().Return(tt.packSizes)
+			mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+			mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Explain(context.Background(), tt.orderSize, domain.CalculateOptions{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalculatePacksUseCase_Explain_InvalidInputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
 
-	result, err = useCase.Execute(0)
+	result, err := useCase.Explain(context.Background(), 0, domain.CalculateOptions{})
 	assert.ErrorIs(t, err, domain.ErrOrderSizePositive)
+	assert.Nil(t, result)
+}
+
+func TestCalculatePacksUseCase_RespectStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetStock().Return([]domain.PackStock{{Size: 500, Available: 0}}).Times(2)
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	opts := domain.CalculateOptions{RespectStock: true}
+
+	result, err := useCase.Execute(context.Background(), 501, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 250, Count: 3}}, result)
+
+	// 750 = 500+250 is out of stock, so three 250s are the only packing.
+	calculation, err := useCase.Explain(context.Background(), 501, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Calculation{
+		Packs:          []domain.PackResult{{Size: 250, Count: 3}},
+		TotalItems:     750,
+		Overshoot:      249,
+		PackCount:      3,
+		Policy:         "fewest-items",
+		DecidedBy:      domain.RuleFewestItems,
+		PackSetVersion: 1,
+	}, calculation)
+}
+
+func TestCalculatePacksUseCase_RespectStock_Insufficient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetStock().Return([]domain.PackStock{{Size: 250, Available: 1}, {Size: 500, Available: 1}})
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	result, err := useCase.Execute(context.Background(), 1000, domain.CalculateOptions{RespectStock: true})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Empty(t, result)
+}
+
+func TestCalculatePacksUseCase_LowestCostPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+	mockRepo.EXPECT().GetCosts().Return([]domain.PackCost{
+		{Size: 250, Cost: 40},
+		{Size: 500, Cost: 100},
+		{Size: 1000, Cost: 170},
+	}).Times(2)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	opts := domain.CalculateOptions{Policy: "lowest-cost"}
+
+	result, err := useCase.Execute(context.Background(), 501, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 250, Count: 3}}, result)
+
+	calculation, err := useCase.Explain(context.Background(), 501, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Calculation{
+		Packs:          []domain.PackResult{{Size: 250, Count: 3}},
+		TotalItems:     750,
+		Overshoot:      249,
+		PackCount:      3,
+		Cost:           120,
+		Policy:         "lowest-cost",
+		DecidedBy:      "lowest-cost",
+		PackSetVersion: 1,
+	}, calculation)
+}
+
+func TestCalculatePacksUseCase_LowestCostPolicy_MissingCosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetCosts().Return([]domain.PackCost{{Size: 250, Cost: 40}})
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	result, err := useCase.Execute(context.Background(), 501, domain.CalculateOptions{Policy: "lowest-cost"})
+
+	assert.ErrorIs(t, err, domain.ErrMissingPackCosts)
+	assert.Empty(t, result)
+}
+
+func TestCalculatePacksUseCase_PackConstraints(t *testing.T) {
+	two := 2
+	constraints := []domain.PackConstraint{
+		{Size: 250, Max: &two},
+		{Size: 5000, Min: 3, OrdersOver: 10000},
+	}
+
+	tests := []struct {
+		name      string
+		orderSize int64
+		expected  []domain.PackResult
+	}{
+		{name: "maximum leaves a larger pack", orderSize: 750, expected: []domain.PackResult{{Size: 1000, Count: 1}}},
+		{name: "below the threshold", orderSize: 10000, expected: []domain.PackResult{{Size: 5000, Count: 2}}},
+		{name: "minimum above the threshold", orderSize: 10001, expected: []domain.PackResult{{Size: 5000, Count: 3}}},
+		{name: "minimum plus the rest", orderSize: 15251, expected: []domain.PackResult{{Size: 5000, Count: 3}, {Size: 250, Count: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 1000, 5000})
+			mockRepo.EXPECT().GetConstraints().Return(constraints)
+
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Execute(context.Background(), tt.orderSize, domain.CalculateOptions{})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalculatePacksUseCase_PackConstraintsUnmet(t *testing.T) {
+	one := 1
+
+	t.Run("capacity", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+		mockRepo.EXPECT().GetConstraints().Return([]domain.PackConstraint{{Size: 250, Max: &one}, {Size: 500, Max: &one}})
+
+		useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+		_, err := useCase.Execute(context.Background(), 1000, domain.CalculateOptions{})
+
+		assert.ErrorIs(t, err, domain.ErrPackConstraintsUnmet)
+		assert.EqualError(t, err, "order cannot meet the pack constraints: at most 750 items fit")
+	})
+
+	t.Run("minimum beyond stock", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+		mockRepo.EXPECT().GetConstraints().Return([]domain.PackConstraint{{Size: 500, Min: 2}})
+		mockRepo.EXPECT().GetStock().Return([]domain.PackStock{{Size: 500, Available: 1}})
+
+		useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+		_, err := useCase.Execute(context.Background(), 100, domain.CalculateOptions{RespectStock: true})
+
+		assert.EqualError(t, err, "order cannot meet the pack constraints: 2 packs of 500 required, 1 in stock")
+	})
+}
+
+func TestCalculatePacksUseCase_MaxOvershoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	limit := int64(249)
+
+	result, err := useCase.Execute(context.Background(), 251, domain.CalculateOptions{MaxOvershoot: &limit})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}}, result)
+
+	limit = 248
+	result, err = useCase.Execute(context.Background(), 251, domain.CalculateOptions{MaxOvershoot: &limit})
+	assert.ErrorIs(t, err, domain.ErrOvershootLimitExceeded)
+	assert.Empty(t, result)
+}
+
+func TestCalculatePacksUseCase_OvershootModes(t *testing.T) {
+	percent := func(p int) *int { return &p }
+	items := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name      string
+		policy    string
+		opts      domain.CalculateOptions
+		orderSize int64
+		expected  []domain.PackResult
+		wantErr   *domain.OvershootError
+	}{
+		{
+			name:      "exact match",
+			opts:      domain.CalculateOptions{ExactOnly: true},
+			orderSize: 750,
+			expected:  []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}},
+		},
+		{
+			name:      "exact refused",
+			opts:      domain.CalculateOptions{ExactOnly: true},
+			orderSize: 251,
+			wantErr:   &domain.OvershootError{OrderSize: 251, MaxOvershoot: 0, NearestBelow: 250, NearestAbove: 500},
+		},
+		{
+			name:      "exact refused below the smallest pack",
+			opts:      domain.CalculateOptions{ExactOnly: true},
+			orderSize: 1,
+			wantErr:   &domain.OvershootError{OrderSize: 1, MaxOvershoot: 0, NearestBelow: 0, NearestAbove: 250},
+		},
+		{
+			name:      "percent within bound",
+			opts:      domain.CalculateOptions{MaxOvershootPercent: percent(100)},
+			orderSize: 251,
+			expected:  []domain.PackResult{{Size: 500, Count: 1}},
+		},
+		{
+			name:      "percent refused",
+			opts:      domain.CalculateOptions{MaxOvershootPercent: percent(50)},
+			orderSize: 251,
+			wantErr:   &domain.OvershootError{OrderSize: 251, MaxOvershoot: 125, NearestBelow: 250, NearestAbove: 500},
+		},
+		{
+			name:      "tightest cap applies",
+			opts:      domain.CalculateOptions{MaxOvershoot: items(300), MaxOvershootPercent: percent(10)},
+			orderSize: 1001,
+			wantErr:   &domain.OvershootError{OrderSize: 1001, MaxOvershoot: 100, NearestBelow: 1000, NearestAbove: 1250},
+		},
+		{
+			name:      "exact refused by the bounded search",
+			opts:      domain.CalculateOptions{ExactOnly: true, Policy: "fewest-packs"},
+			orderSize: 251,
+			wantErr:   &domain.OvershootError{OrderSize: 251, MaxOvershoot: 0, NearestBelow: 250, NearestAbove: 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000})
+			mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Execute(context.Background(), tt.orderSize, tt.opts)
+
+			if tt.wantErr != nil {
+				var overshootErr *domain.OvershootError
+				assert.ErrorAs(t, err, &overshootErr)
+				assert.Equal(t, tt.wantErr, overshootErr)
+				assert.ErrorIs(t, err, domain.ErrOvershootLimitExceeded)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalculatePacksUseCase_InvalidOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	_, err := useCase.Execute(context.Background(), 100, domain.CalculateOptions{Policy: "cheapest"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPolicy)
+
+	negative := int64(-1)
+	_, err = useCase.Explain(context.Background(), 100, domain.CalculateOptions{MaxOvershoot: &negative})
+	assert.ErrorIs(t, err, domain.ErrInvalidOvershoot)
+
+	tooMuch := 1001
+	_, err = useCase.Execute(context.Background(), 100, domain.CalculateOptions{MaxOvershootPercent: &tooMuch})
+	assert.ErrorIs(t, err, domain.ErrInvalidOvershoot)
+}
+
+func TestCalculatePacksUseCase_Int64Orders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000, 2000, 5000}).Times(3)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	// 9223372036854765807 rounds up to the next multiple of 250.
+	calculation, err := useCase.Explain(context.Background(), math.MaxInt64-10_000, domain.CalculateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Calculation{
+		Packs:              []domain.PackResult{{Size: 5000, Count: 1_844_674_407_370_953}, {Size: 1000, Count: 1}},
+		TotalItems:         9_223_372_036_854_766_000,
+		Overshoot:          193,
+		PackCount:          1_844_674_407_370_954,
+		Policy:             "fewest-items",
+		DecidedBy:          domain.RuleFewestPacks,
+		LargeOrderShortcut: true,
+		PackSetVersion:     1,
+	}, calculation)
+
+	// No multiple of 250 fits between the order and the int64 limit.
+	result, err := useCase.Execute(context.Background(), math.MaxInt64, domain.CalculateOptions{})
+	assert.ErrorIs(t, err, domain.ErrTotalOverflow)
+	assert.Empty(t, result)
+
+	// A cap must not overflow either: 0% of the order allows no overshoot.
+	zero := 0
+	_, err = useCase.Execute(context.Background(), math.MaxInt64-10_000, domain.CalculateOptions{MaxOvershootPercent: &zero})
+	var overshootErr *domain.OvershootError
+	assert.ErrorAs(t, err, &overshootErr)
+	assert.Equal(t, &domain.OvershootError{
+		OrderSize:    math.MaxInt64 - 10_000,
+		NearestBelow: 9_223_372_036_854_765_750,
+		NearestAbove: 9_223_372_036_854_766_000,
+	}, overshootErr)
+}
+
+func TestCalculatePacksUseCase_Execute_Canceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	result, err := useCase.Execute(ctx, 1000, domain.CalculateOptions{})
+
+	assert.ErrorIs(t, err, domain.ErrCalculationCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, result)
+}
+
+func TestCalculatePacksUseCase_Execute_ComputeBudgetExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{999983, 999999, 1000000})
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithComputeBudget(time.Nanosecond))
+	result, err := useCase.Execute(context.Background(), 1000, domain.CalculateOptions{})
+
+	assert.ErrorIs(t, err, domain.ErrComputeBudgetExceeded)
+	assert.NotErrorIs(t, err, domain.ErrCalculationCanceled)
 	assert.Empty(t, result)
 }
 
 func BenchmarkCalculateOptimalPacks(b *testing.B) {
-	sizes := []int{250, 500, 1000, 2000, 5000}
+	table := mustPackTable(b, 250, 500, 1000, 2000, 5000)
 	for i := 0; i < b.N; i++ {
-		calculateOptimalPacks(12001, sizes)
+		_, _ = calculateOptimalPacks(context.Background(), 12001, table)
 	}
 }
 
 func BenchmarkCalculateOptimalPacks_EdgeCase(b *testing.B) {
-	sizes := []int{17, 31, 47}
+	table := mustPackTable(b, 17, 31, 47)
 	for i := 0; i < b.N; i++ {
-		calculateOptimalPacks(5000, sizes)
+		_, _ = calculateOptimalPacks(context.Background(), 5000, table)
 	}
 }
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// flight is one calculation shared by every caller that asked for it while it
+// ran.
+type flight struct {
+	done    chan struct{}
+	packs   []domain.PackResult
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightGroup coalesces identical concurrent calculations: the first caller
+// for a key starts it and later callers wait for the same result. The
+// calculation runs apart from the callers, so one giving up does not fail the
+// others; it is canceled once every caller has given up.
+type flightGroup struct {
+	mu           sync.Mutex
+	flights      map[resultKey]*flight
+	calls        uint64
+	deduplicated uint64
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[resultKey]*flight)}
+}
+
+// do returns the result of fn for key, joining a calculation already running
+// for it. fn runs under a context of its own that keeps the values of the
+// starting caller's ctx and has a compute budget of its own, so callers
+// joining later cannot keep it running past budget (0 for none). Each caller
+// gets its own copy of the packs.
+func (g *flightGroup) do(
+	ctx context.Context,
+	key resultKey,
+	budget time.Duration,
+	fn func(ctx context.Context) ([]domain.PackResult, error),
+) ([]domain.PackResult, error) {
+	g.mu.Lock()
+	g.calls++
+	f, ok := g.flights[key]
+	if ok {
+		g.deduplicated++
+	} else {
+		flightCtx, cancel := withComputeBudget(context.WithoutCancel(ctx), budget)
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go g.run(flightCtx, key, f, fn)
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return slices.Clone(f.packs), f.err
+	case <-ctx.Done():
+		g.leave(key, f)
+		return nil, contextError(ctx)
+	}
+}
+
+func (g *flightGroup) run(
+	ctx context.Context,
+	key resultKey,
+	f *flight,
+	fn func(ctx context.Context) ([]domain.PackResult, error),
+) {
+	defer close(f.done)
+	defer f.cancel()
+	defer func() {
+		// Nobody up the stack of this goroutine could recover a panic, so it
+		// fails the callers instead of the server.
+		if r := recover(); r != nil {
+			f.packs, f.err = nil, fmt.Errorf("calculation failed: %v", r)
+		}
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+	}()
+
+	f.packs, f.err = fn(ctx)
+}
+
+// leave drops a caller that gave up, canceling the calculation if it was the
+// last one. Callers arriving later start afresh.
+func (g *flightGroup) leave(key resultKey, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f.waiters--
+	if f.waiters == 0 {
+		f.cancel()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+	}
+}
+
+func (g *flightGroup) stats() domain.CoalescingStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return domain.CoalescingStats{
+		Enabled:      true,
+		Calls:        g.calls,
+		Deduplicated: g.deduplicated,
+		InFlight:     len(g.flights),
+	}
+}
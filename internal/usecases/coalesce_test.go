@@ -0,0 +1,202 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var flightKey = newResultKey(1, 501, FewestItems{}, domain.CalculateOptions{})
+
+// waitForCalls waits until n callers have reached the group.
+func waitForCalls(t *testing.T, g *flightGroup, n uint64) {
+	t.Helper()
+	require.Eventually(t, func() bool { return g.stats().Calls == n }, time.Second, time.Millisecond)
+}
+
+func TestFlightGroup_SharesOneCalculation(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	var runs atomic.Int32
+	fn := func(context.Context) ([]domain.PackResult, error) {
+		runs.Add(1)
+		<-release
+		return []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, nil
+	}
+
+	const callers = 10
+	results := make([][]domain.PackResult, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			packs, err := g.do(context.Background(), flightKey, 0, fn)
+			assert.NoError(t, err)
+			results[i] = packs
+		})
+	}
+	waitForCalls(t, g, callers)
+	assert.Equal(t, domain.CoalescingStats{Enabled: true, Calls: callers, Deduplicated: callers - 1, InFlight: 1}, g.stats())
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), runs.Load())
+	for _, packs := range results {
+		assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, packs)
+	}
+	// Every caller has its own copy.
+	results[0][0].Count = 7
+	assert.Equal(t, int64(1), results[1][0].Count)
+	assert.Equal(t, 0, g.stats().InFlight)
+
+	// A later call starts afresh.
+	_, err := g.do(context.Background(), flightKey, 0, fn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestFlightGroup_DifferentKeysRunApart(t *testing.T) {
+	g := newFlightGroup()
+	var runs atomic.Int32
+	fn := func(context.Context) ([]domain.PackResult, error) {
+		runs.Add(1)
+		return nil, nil
+	}
+
+	other := newResultKey(1, 501, FewestPacks{}, domain.CalculateOptions{})
+	_, err := g.do(context.Background(), flightKey, 0, fn)
+	require.NoError(t, err)
+	_, err = g.do(context.Background(), other, 0, fn)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), runs.Load())
+	assert.Equal(t, uint64(0), g.stats().Deduplicated)
+}
+
+func TestFlightGroup_CallerGivingUpLeavesOthers(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	fn := func(ctx context.Context) ([]domain.PackResult, error) {
+		select {
+		case <-release:
+			return []domain.PackResult{{Size: 250, Count: 1}}, nil
+		case <-ctx.Done():
+			return nil, contextError(ctx)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, flightKey, 0, fn)
+		first <- err
+	}()
+	waitForCalls(t, g, 1)
+
+	second := make(chan []domain.PackResult, 1)
+	go func() {
+		packs, err := g.do(context.Background(), flightKey, 0, fn)
+		assert.NoError(t, err)
+		second <- packs
+	}()
+	waitForCalls(t, g, 2)
+
+	cancel()
+	assert.ErrorIs(t, <-first, domain.ErrCalculationCanceled)
+
+	close(release)
+	assert.Equal(t, []domain.PackResult{{Size: 250, Count: 1}}, <-second)
+}
+
+func TestFlightGroup_AllCallersGivingUpCancels(t *testing.T) {
+	g := newFlightGroup()
+	canceled := make(chan struct{})
+	fn := func(ctx context.Context) ([]domain.PackResult, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, contextError(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := g.do(ctx, flightKey, 0, fn)
+	assert.ErrorIs(t, err, domain.ErrCalculationCanceled)
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("calculation was not canceled")
+	}
+	assert.Equal(t, 0, g.stats().InFlight)
+}
+
+func TestFlightGroup_ComputeBudget(t *testing.T) {
+	g := newFlightGroup()
+	fn := func(ctx context.Context) ([]domain.PackResult, error) {
+		<-ctx.Done()
+		return nil, contextError(ctx)
+	}
+
+	ctx, cancel := withComputeBudget(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := g.do(ctx, flightKey, 0, fn)
+	assert.ErrorIs(t, err, domain.ErrComputeBudgetExceeded)
+}
+
+func TestFlightGroup_FlightBudget(t *testing.T) {
+	g := newFlightGroup()
+	fn := func(ctx context.Context) ([]domain.PackResult, error) {
+		<-ctx.Done()
+		return nil, contextError(ctx)
+	}
+
+	// The callers never give up, so only the flight's own budget ends it.
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Go(func() {
+			_, err := g.do(context.Background(), flightKey, 10*time.Millisecond, fn)
+			assert.ErrorIs(t, err, domain.ErrComputeBudgetExceeded)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, 0, g.stats().InFlight)
+}
+
+func TestFlightGroup_Panic(t *testing.T) {
+	g := newFlightGroup()
+	_, err := g.do(context.Background(), flightKey, 0, func(context.Context) ([]domain.PackResult, error) {
+		panic("boom")
+	})
+	assert.EqualError(t, err, "calculation failed: boom")
+	assert.Equal(t, 0, g.stats().InFlight)
+}
+
+func TestCalculatePacksUseCase_Coalescing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(3)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).Times(2)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithCoalescing())
+	for range 2 {
+		packs, err := useCase.Execute(context.Background(), 501, domain.CalculateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, packs)
+	}
+
+	// Calls one after another have nothing to share.
+	stats := useCase.Stats()
+	assert.Equal(t, domain.CoalescingStats{Enabled: true, Calls: 2}, stats.Coalescing)
+	assert.Equal(t, domain.ResultCacheStats{}, stats.ResultCache)
+}
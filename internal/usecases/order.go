@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+)
+
+// maxOrderLines bounds the work a single order request can ask for.
+const maxOrderLines = 100
+
+// CalculateOrderUseCase packs multi-line orders, each line with the pack
+// sizes of its product.
+type CalculateOrderUseCase struct {
+	products domain.ProductRepository
+	packs    *CalculatePacksUseCase
+	tables   *PackTablesByID // by product ID
+}
+
+// NewCalculateOrderUseCase solves lines with packs, sharing its default
+// policy and compute budget. tables holds the products' solver
+// tables and is shared with the ProductsUseCase that evicts them.
+func NewCalculateOrderUseCase(
+	products domain.ProductRepository,
+	packs *CalculatePacksUseCase,
+	tables *PackTablesByID,
+) *CalculateOrderUseCase {
+	return &CalculateOrderUseCase{
+		products: products,
+		packs:    packs,
+		tables:   tables,
+	}
+}
+
+// Execute packs every line under the same options and sums the totals. The
+// compute budget covers the whole order. The stock, costs and pack
+// constraints of the main pack set belong to its sizes, not the products':
+// RespectStock is ignored, and policies ranking by cost fail with
+// ErrMissingPackCosts. Errors name the line they came from; ErrTotalOverflow
+// reports order totals beyond the int64 range.
+func (uc *CalculateOrderUseCase) Execute(
+	ctx context.Context,
+	lines []domain.OrderLine,
+	opts domain.CalculateOptions,
+) (*domain.OrderResult, error) {
+	if len(lines) == 0 {
+		return nil, domain.ErrEmptyOrder
+	}
+	if len(lines) > maxOrderLines {
+		return nil, domain.ErrTooManyOrderLines
+	}
+
+	policy, err := uc.packs.resolvePolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+	if usesCosts(policy) {
+		return nil, fmt.Errorf("%w: products have no pack costs", domain.ErrMissingPackCosts)
+	}
+	opts.RespectStock = false
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	result := &domain.OrderResult{Lines: make([]domain.LineResult, 0, len(lines))}
+	for i, line := range lines {
+		lineResult, err := uc.line(ctx, line, policy, opts)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		result.Lines = append(result.Lines, lineResult)
+
+		// Overshoot and pack count never exceed the items.
+		var ok bool
+		if result.TotalItems, ok = checkedAdd(result.TotalItems, lineResult.TotalItems); !ok {
+			return nil, domain.ErrTotalOverflow
+		}
+		result.Overshoot += lineResult.Overshoot
+		result.PackCount += lineResult.PackCount
+	}
+	return result, nil
+}
+
+// line packs one order line.
+func (uc *CalculateOrderUseCase) line(
+	ctx context.Context,
+	line domain.OrderLine,
+	policy Policy,
+	opts domain.CalculateOptions,
+) (domain.LineResult, error) {
+	if line.Quantity <= 0 {
+		return domain.LineResult{}, domain.ErrOrderSizePositive
+	}
+
+	product, ok := uc.products.GetProduct(line.ProductID)
+	if !ok {
+		return domain.LineResult{}, domain.ErrProductNotFound
+	}
+	if len(product.PackSizes) == 0 {
+		return domain.LineResult{}, domain.ErrNoPackSizes
+	}
+
+	table, err := uc.tables.get(product.ID).get(ctx, product.PackSizes)
+	if err != nil {
+		return domain.LineResult{}, err
+	}
+
+	sol, err := uc.packs.solve(ctx, line.Quantity, &packState{table: table}, policy, opts)
+	if err != nil {
+		return domain.LineResult{}, err
+	}
+
+	return domain.LineResult{
+		ProductID:  product.ID,
+		Quantity:   line.Quantity,
+		Packs:      toPackResults(sol.counts),
+		TotalItems: sol.total,
+		Overshoot:  sol.total - line.Quantity,
+		PackCount:  sumCounts(sol.counts),
+	}, nil
+}
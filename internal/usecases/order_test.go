@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalculateOrderUseCase_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProducts := mocks.NewMockProductRepository(ctrl)
+	mockProducts.EXPECT().GetProduct("widget").Return(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 500, 1000}}, true)
+	mockProducts.EXPECT().GetProduct("bolt").Return(domain.Product{ID: "bolt", PackSizes: []domain.PackSize{12, 50}}, true)
+
+	uc := NewCalculateOrderUseCase(mockProducts, NewCalculatePacksUseCase(mocks.NewMockPackSizeRepository(ctrl), NewPackTableCache()), NewPackTablesByID(10))
+	result, err := uc.Execute(context.Background(), []domain.OrderLine{
+		{ProductID: "widget", Quantity: 501},
+		{ProductID: "bolt", Quantity: 61},
+	}, domain.CalculateOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.OrderResult{
+		Lines: []domain.LineResult{
+			{ProductID: "widget", Quantity: 501, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, Overshoot: 249, PackCount: 2},
+			{ProductID: "bolt", Quantity: 61, Packs: []domain.PackResult{{Size: 50, Count: 1}, {Size: 12, Count: 1}}, TotalItems: 62, Overshoot: 1, PackCount: 2},
+		},
+		TotalItems: 812,
+		Overshoot:  250,
+		PackCount:  4,
+	}, result)
+}
+
+func TestCalculateOrderUseCase_IgnoresMainPackSetStockAndCosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProducts := mocks.NewMockProductRepository(ctrl)
+	mockProducts.EXPECT().GetProduct("widget").Return(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 500}}, true).Times(2)
+	// The main pack set's stock and costs are never read.
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+
+	uc := NewCalculateOrderUseCase(mockProducts, NewCalculatePacksUseCase(mockRepo, NewPackTableCache()), NewPackTablesByID(10))
+	result, err := uc.Execute(context.Background(), []domain.OrderLine{
+		{ProductID: "widget", Quantity: 500},
+		{ProductID: "widget", Quantity: 500},
+	}, domain.CalculateOptions{RespectStock: true})
+
+	// Every line ships its own 500.
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}}, result.Lines[0].Packs)
+	assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}}, result.Lines[1].Packs)
+
+	_, err = uc.Execute(context.Background(), []domain.OrderLine{{ProductID: "widget", Quantity: 500}}, domain.CalculateOptions{Policy: "lowest-cost"})
+	assert.ErrorIs(t, err, domain.ErrMissingPackCosts)
+}
+
+func TestCalculateOrderUseCase_IgnoresMainPackSetConstraints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProducts := mocks.NewMockProductRepository(ctrl)
+	mockProducts.EXPECT().GetProduct("widget").Return(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 500}}, true)
+
+	// The main pack set allows no 500s and requires a 1000, which the product
+	// does not even have.
+	none := 0
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetConstraints().Return([]domain.PackConstraint{
+		{Size: 500, Max: &none},
+		{Size: 1000, Min: 1},
+	}).AnyTimes()
+
+	uc := NewCalculateOrderUseCase(mockProducts, NewCalculatePacksUseCase(mockRepo, NewPackTableCache()), NewPackTablesByID(10))
+	result, err := uc.Execute(context.Background(), []domain.OrderLine{{ProductID: "widget", Quantity: 500}}, domain.CalculateOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}}, result.Lines[0].Packs)
+}
+
+func TestCalculateOrderUseCase_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []domain.OrderLine
+		setup   func(m *mocks.MockProductRepository)
+		opts    domain.CalculateOptions
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "empty order",
+			wantErr: domain.ErrEmptyOrder,
+		},
+		{
+			name:    "too many lines",
+			lines:   make([]domain.OrderLine, maxOrderLines+1),
+			wantErr: domain.ErrTooManyOrderLines,
+		},
+		{
+			name:    "invalid policy",
+			lines:   []domain.OrderLine{{ProductID: "widget", Quantity: 1}},
+			opts:    domain.CalculateOptions{Policy: "cheapest"},
+			wantErr: domain.ErrInvalidPolicy,
+		},
+		{
+			name:  "unknown product",
+			lines: []domain.OrderLine{{ProductID: "widget", Quantity: 1}, {ProductID: "nut", Quantity: 1}},
+			setup: func(m *mocks.MockProductRepository) {
+				m.EXPECT().GetProduct("widget").Return(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250}}, true)
+				m.EXPECT().GetProduct("nut").Return(domain.Product{}, false)
+			},
+			wantErr: domain.ErrProductNotFound,
+			wantMsg: "line 2: product not found",
+		},
+		{
+			name:  "order total beyond the int64 limit",
+			lines: []domain.OrderLine{{ProductID: "widget", Quantity: 1 << 62}, {ProductID: "widget", Quantity: 1 << 62}},
+			setup: func(m *mocks.MockProductRepository) {
+				m.EXPECT().GetProduct("widget").Return(domain.Product{ID: "widget", PackSizes: []domain.PackSize{1}}, true).Times(2)
+			},
+			wantErr: domain.ErrTotalOverflow,
+			wantMsg: "order total exceeds the 64-bit range",
+		},
+		{
+			name:    "non-positive quantity",
+			lines:   []domain.OrderLine{{ProductID: "widget", Quantity: 0}},
+			wantErr: domain.ErrOrderSizePositive,
+			wantMsg: "line 1: order size must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProducts := mocks.NewMockProductRepository(ctrl)
+			if tt.setup != nil {
+				tt.setup(mockProducts)
+			}
+
+			uc := NewCalculateOrderUseCase(mockProducts, NewCalculatePacksUseCase(mocks.NewMockPackSizeRepository(ctrl), NewPackTableCache()), NewPackTablesByID(10))
+			result, err := uc.Execute(context.Background(), tt.lines, tt.opts)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, result)
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import "math"
+
+// Order sizes and totals use the full int64 range, so arithmetic near the
+// top of it must not wrap. All values here are non-negative.
+
+// checkedAdd returns a+b, or false if the sum does not fit in an int64.
+func checkedAdd(a, b int64) (int64, bool) {
+	if a > math.MaxInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// checkedMul returns a*b, or false if the product does not fit in an int64.
+func checkedMul(a, b int64) (int64, bool) {
+	if b != 0 && a > math.MaxInt64/b {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// saturatingAdd returns a+b, or math.MaxInt64 if the sum does not fit.
+func saturatingAdd(a, b int64) int64 {
+	if sum, ok := checkedAdd(a, b); ok {
+		return sum
+	}
+	return math.MaxInt64
+}
+
+// percentOf returns n*percent/100 rounded down, or math.MaxInt64 if that does
+// not fit.
+func percentOf(n int64, percent int) int64 {
+	p := int64(percent)
+	if p != 0 && n/100 > math.MaxInt64/p {
+		return math.MaxInt64
+	}
+	return saturatingAdd(n/100*p, n%100*p/100)
+}
+
+// ceilDiv returns a/b rounded up, for a >= 0 and b > 0.
+func ceilDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 {
+		q++
+	}
+	return q
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckedAdd(t *testing.T) {
+	sum, ok := checkedAdd(math.MaxInt64-1, 1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(math.MaxInt64), sum)
+
+	_, ok = checkedAdd(math.MaxInt64, 1)
+	assert.False(t, ok)
+
+	assert.Equal(t, int64(math.MaxInt64), saturatingAdd(math.MaxInt64-5, 10))
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		n       int64
+		percent int
+		want    int64
+	}{
+		{n: 251, percent: 50, want: 125},
+		{n: 1001, percent: 10, want: 100},
+		{n: 99, percent: 0, want: 0},
+		{n: math.MaxInt64, percent: 100, want: math.MaxInt64},
+		{n: math.MaxInt64, percent: 50, want: math.MaxInt64 / 2},
+		{n: math.MaxInt64, percent: 1000, want: math.MaxInt64},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, percentOf(tt.n, tt.percent), "%d%% of %d", tt.percent, tt.n)
+	}
+}
+
+func TestCeilDiv(t *testing.T) {
+	assert.Equal(t, int64(0), ceilDiv(0, 7))
+	assert.Equal(t, int64(1), ceilDiv(7, 7))
+	assert.Equal(t, int64(2), ceilDiv(8, 7))
+	assert.Equal(t, int64(math.MaxInt64/2+1), ceilDiv(math.MaxInt64, 2))
+}
@@ -2,30 +2,71 @@ package usecases
 
 import (
 	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
 	"sort"
 )
 
 const (
 	maxPackSize  = 1_000_000
 	maxPackCount = 20
+	// maxPackCost keeps summed costs of the bounded DP within int64.
+	maxPackCost = 1_000_000_000
+	// maxConstrainedPacks bounds the counts a pack constraint may set.
+	maxConstrainedPacks = 10_000
+	// maxPackWeight (grams) and maxPackDimension (centimetres) keep the
+	// weight and volume of a pack well within int64.
+	maxPackWeight    = 1_000_000_000
+	maxPackDimension = 10_000
+	// maxPackagingLevels and maxPacksPerUnit bound a packaging hierarchy;
+	// the packs in its largest unit must fit in int64.
+	maxPackagingLevels  = 5
+	maxPackagingNameLen = 32
+	maxPacksPerUnit     = 1_000_000_000_000
+	// maxPrice bounds unit prices and surcharges, in currency units.
+	maxPrice         = 1_000_000
+	maxDiscountTiers = 10
 )
 
+// currencyPattern matches ISO 4217 currency codes.
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 type PackSizesUseCase struct {
-	repo domain.PackSizeRepository
+	repo   domain.PackSizeRepository
+	tables *PackTableCache
 }
 
-func NewPackSizesUseCase(repo domain.PackSizeRepository) *PackSizesUseCase {
-	return &PackSizesUseCase{repo: repo}
+func NewPackSizesUseCase(repo domain.PackSizeRepository, tables *PackTableCache) *PackSizesUseCase {
+	return &PackSizesUseCase{repo: repo, tables: tables}
 }
 
 func (uc *PackSizesUseCase) UpdatePackSizes(sizes []domain.PackSize) error {
+	unique, err := validatePackSizes(sizes)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.UpdatePackSizes(unique); err != nil {
+		return err
+	}
+
+	// Build the DP table now rather than on the first calculation.
+	uc.tables.warm(unique)
+	return nil
+}
+
+// validatePackSizes checks a pack set and returns it deduplicated and sorted.
+func validatePackSizes(sizes []domain.PackSize) ([]domain.PackSize, error) {
 	if len(sizes) == 0 {
-		return domain.ErrEmptyPackSizes
+		return nil, domain.ErrEmptyPackSizes
 	}
 
 	for _, size := range sizes {
 		if size <= 0 || int(size) > maxPackSize {
-			return domain.ErrInvalidPackSize
+			return nil, domain.ErrInvalidPackSize
 		}
 	}
 
@@ -42,12 +83,237 @@ func (uc *PackSizesUseCase) UpdatePackSizes(sizes []domain.PackSize) error {
 	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
 
 	if len(unique) > maxPackCount {
-		return domain.ErrTooManyPackSizes
+		return nil, domain.ErrTooManyPackSizes
 	}
-
-	return uc.repo.UpdatePackSizes(unique)
+	return unique, nil
 }
 
 func (uc *PackSizesUseCase) GetPackSizes() []domain.PackSize {
 	return uc.repo.GetPackSizes()
 }
+
+// Analyze reports the GCD, Frobenius number and redundant sizes of the current
+// pack set. They come from the solver's tables, so this is free once the set
+// has been warmed.
+func (uc *PackSizesUseCase) Analyze(ctx context.Context) (*domain.PackSetAnalysis, error) {
+	sizes := uc.repo.GetPackSizes()
+	if len(sizes) == 0 {
+		return nil, domain.ErrNoPackSizes
+	}
+
+	table, err := uc.tables.get(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+	return table.analysis(), nil
+}
+
+// UpdateStock replaces the stock levels. Entries may name sizes outside the
+// current pack set; they are ignored until the size is added.
+func (uc *PackSizesUseCase) UpdateStock(stock []domain.PackStock) error {
+	sorted, err := validateStock(stock)
+	if err != nil {
+		return err
+	}
+	return uc.repo.UpdateStock(sorted)
+}
+
+// validateStock checks stock levels and returns them sorted by size.
+func validateStock(stock []domain.PackStock) ([]domain.PackStock, error) {
+	seen := make(map[domain.PackSize]bool, len(stock))
+	for _, entry := range stock {
+		if entry.Size <= 0 || int(entry.Size) > maxPackSize || entry.Available < 0 || seen[entry.Size] {
+			return nil, domain.ErrInvalidStock
+		}
+		seen[entry.Size] = true
+	}
+
+	sorted := make([]domain.PackStock, len(stock))
+	copy(sorted, stock)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Size < sorted[j].Size })
+	return sorted, nil
+}
+
+func (uc *PackSizesUseCase) GetStock() []domain.PackStock {
+	return uc.repo.GetStock()
+}
+
+// UpdateCosts replaces the pack costs used by the cost objective. Like stock,
+// entries may name sizes outside the current pack set.
+func (uc *PackSizesUseCase) UpdateCosts(costs []domain.PackCost) error {
+	seen := make(map[domain.PackSize]bool, len(costs))
+	for _, entry := range costs {
+		if entry.Size <= 0 || int(entry.Size) > maxPackSize || entry.Cost <= 0 || entry.Cost > maxPackCost || seen[entry.Size] {
+			return domain.ErrInvalidPackCost
+		}
+		seen[entry.Size] = true
+	}
+
+	sorted := make([]domain.PackCost, len(costs))
+	copy(sorted, costs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Size < sorted[j].Size })
+
+	return uc.repo.UpdateCosts(sorted)
+}
+
+func (uc *PackSizesUseCase) GetCosts() []domain.PackCost {
+	return uc.repo.GetCosts()
+}
+
+// UpdateDimensions replaces the pack weights and sizes used to plan parcels.
+// Like stock, entries may name sizes outside the current pack set.
+func (uc *PackSizesUseCase) UpdateDimensions(dimensions []domain.PackDimensions) error {
+	seen := make(map[domain.PackSize]bool, len(dimensions))
+	for _, d := range dimensions {
+		if d.Size <= 0 || int(d.Size) > maxPackSize || seen[d.Size] ||
+			d.Weight <= 0 || d.Weight > maxPackWeight ||
+			d.Length <= 0 || d.Length > maxPackDimension ||
+			d.Width <= 0 || d.Width > maxPackDimension ||
+			d.Height <= 0 || d.Height > maxPackDimension {
+			return domain.ErrInvalidPackDimensions
+		}
+		seen[d.Size] = true
+	}
+
+	sorted := make([]domain.PackDimensions, len(dimensions))
+	copy(sorted, dimensions)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Size < sorted[j].Size })
+
+	return uc.repo.UpdateDimensions(sorted)
+}
+
+func (uc *PackSizesUseCase) GetDimensions() []domain.PackDimensions {
+	return uc.repo.GetDimensions()
+}
+
+// UpdateHierarchies replaces the packaging hierarchies packs consolidate
+// into. Like stock, entries may name sizes outside the current pack set.
+// Every level must hold at least two units of the one below, under a name of
+// its own.
+func (uc *PackSizesUseCase) UpdateHierarchies(hierarchies []domain.PackHierarchy) error {
+	seen := make(map[domain.PackSize]bool, len(hierarchies))
+	for _, h := range hierarchies {
+		if h.Size <= 0 || int(h.Size) > maxPackSize || seen[h.Size] || len(h.Levels) == 0 || len(h.Levels) > maxPackagingLevels {
+			return domain.ErrInvalidPackaging
+		}
+		seen[h.Size] = true
+
+		names := make(map[string]bool, len(h.Levels))
+		packs := int64(1)
+		for _, level := range h.Levels {
+			if level.Name == "" || len(level.Name) > maxPackagingNameLen || names[level.Name] ||
+				level.Contains < 2 || level.Contains > maxPacksPerUnit/packs {
+				return domain.ErrInvalidPackaging
+			}
+			names[level.Name] = true
+			packs *= level.Contains
+		}
+	}
+
+	sorted := make([]domain.PackHierarchy, len(hierarchies))
+	copy(sorted, hierarchies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Size < sorted[j].Size })
+
+	return uc.repo.UpdateHierarchies(sorted)
+}
+
+func (uc *PackSizesUseCase) GetHierarchies() []domain.PackHierarchy {
+	return uc.repo.GetHierarchies()
+}
+
+// UpdatePricing replaces the prices quotes are made from. Like stock, entries
+// may name sizes outside the current pack set. Prices and surcharges run from
+// 0 to maxPrice and discounts from above 0 to 100 percent, each tier of a
+// size starting at a different pack count.
+func (uc *PackSizesUseCase) UpdatePricing(pricing domain.Pricing) error {
+	if !currencyPattern.MatchString(pricing.Currency) {
+		return domain.ErrInvalidPricing
+	}
+
+	limit := domain.Decimal(maxPrice * domain.DecimalScale)
+	seen := make(map[domain.PackSize]bool, len(pricing.Prices))
+	prices := make([]domain.PackPrice, len(pricing.Prices))
+	for i, p := range pricing.Prices {
+		if p.Size <= 0 || int(p.Size) > maxPackSize || seen[p.Size] ||
+			p.UnitPrice < 0 || p.UnitPrice > limit || p.Surcharge < 0 || p.Surcharge > limit ||
+			len(p.DiscountTiers) > maxDiscountTiers {
+			return domain.ErrInvalidPricing
+		}
+		seen[p.Size] = true
+
+		tiers := make([]domain.DiscountTier, len(p.DiscountTiers))
+		copy(tiers, p.DiscountTiers)
+		sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinPacks < tiers[j].MinPacks })
+		for j, tier := range tiers {
+			if tier.MinPacks < 1 || tier.Percent <= 0 || tier.Percent > 100*domain.DecimalScale ||
+				j > 0 && tier.MinPacks == tiers[j-1].MinPacks {
+				return domain.ErrInvalidPricing
+			}
+		}
+		prices[i] = domain.PackPrice{Size: p.Size, UnitPrice: p.UnitPrice, Surcharge: p.Surcharge, DiscountTiers: tiers}
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Size < prices[j].Size })
+
+	return uc.repo.UpdatePricing(domain.Pricing{Currency: pricing.Currency, Prices: prices})
+}
+
+func (uc *PackSizesUseCase) GetPricing() domain.Pricing {
+	return uc.repo.GetPricing()
+}
+
+// UpdateConstraints replaces the per-order pack constraints. Like stock,
+// entries may name sizes outside the current pack set. Constraints no order
+// could meet are rejected: a size that must take more packs than it may for
+// some orders, or maxima on every current size, which leave large orders
+// nothing to ship.
+func (uc *PackSizesUseCase) UpdateConstraints(constraints []domain.PackConstraint) error {
+	type key struct {
+		size       domain.PackSize
+		ordersOver int64
+	}
+	seen := make(map[key]bool, len(constraints))
+	for _, c := range constraints {
+		k := key{c.Size, c.OrdersOver}
+		// No order is over math.MaxInt64 items, so a constraint there would
+		// never apply.
+		if c.Size <= 0 || int(c.Size) > maxPackSize || c.OrdersOver < 0 || c.OrdersOver == math.MaxInt64 || seen[k] ||
+			c.Min < 0 || c.Min > maxConstrainedPacks ||
+			c.Max != nil && (*c.Max < 0 || *c.Max > maxConstrainedPacks) ||
+			c.Min == 0 && c.Max == nil {
+			return domain.ErrInvalidPackConstraint
+		}
+		seen[k] = true
+	}
+
+	// The bounds on a size only change at its thresholds, so checking the
+	// orders just above each covers every order.
+	for _, c := range constraints {
+		mins, maxes, ok := constraintBounds([]int{int(c.Size)}, constraints, c.OrdersOver+1)
+		if ok && maxes[0] >= 0 && mins[0] > maxes[0] {
+			return fmt.Errorf("%w: orders over %d items need at least %d and at most %d packs of %d",
+				domain.ErrUnsatisfiablePackConstraint, c.OrdersOver, mins[0], maxes[0], c.Size)
+		}
+	}
+
+	if current := uc.repo.GetPackSizes(); len(current) > 0 {
+		sizes := normalizePackSizes(current)
+		if _, maxes, ok := constraintBounds(sizes, constraints, math.MaxInt64); ok && !slices.Contains(maxes, -1) {
+			return fmt.Errorf("%w: every pack size is capped, so large orders cannot ship", domain.ErrUnsatisfiablePackConstraint)
+		}
+	}
+
+	sorted := make([]domain.PackConstraint, len(constraints))
+	copy(sorted, constraints)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Size != sorted[j].Size {
+			return sorted[i].Size < sorted[j].Size
+		}
+		return sorted[i].OrdersOver < sorted[j].OrdersOver
+	})
+
+	return uc.repo.UpdateConstraints(sorted)
+}
+
+func (uc *PackSizesUseCase) GetConstraints() []domain.PackConstraint {
+	return uc.repo.GetConstraints()
+}
@@ -3,9 +3,12 @@ package usecases
 import (
 	"calculate_product_packs/internal/domain"
 	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
@@ -68,7 +71,7 @@ func TestPackSizesUseCase_UpdatePackSizes(t *testing.T) {
 				mockRepo.EXPECT().UpdatePackSizes(tt.stored).Return(nil)
 			}
 
-			uc := NewPackSizesUseCase(mockRepo)
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
 			err := uc.UpdatePackSizes(tt.sizes)
 
 			if tt.wantErr != nil {
@@ -88,6 +91,439 @@ func TestPackSizesUseCase_GetPackSizes(t *testing.T) {
 	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
 	mockRepo.EXPECT().GetPackSizes().Return(expected)
 
-	uc := NewPackSizesUseCase(mockRepo)
+	uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
 	assert.Equal(t, expected, uc.GetPackSizes())
 }
+
+func TestPackSizesUseCase_UpdatePackSizes_WarmsTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().UpdatePackSizes([]domain.PackSize{23, 31, 53}).Return(nil)
+
+	cache := NewPackTableCache()
+	uc := NewPackSizesUseCase(mockRepo, cache)
+	require.NoError(t, uc.UpdatePackSizes([]domain.PackSize{53, 31, 23}))
+
+	table := cache.current.Load()
+	require.NotNil(t, table)
+	assert.Equal(t, []int{23, 31, 53}, table.sizes)
+}
+
+func TestPackSizesUseCase_Analyze(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{6, 10, 15, 30})
+	mockRepo.EXPECT().GetPackSizes().Return(nil)
+
+	uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+	analysis, err := uc.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &domain.PackSetAnalysis{
+		PackSizes:       []domain.PackSize{6, 10, 15, 30},
+		GCD:             1,
+		FrobeniusNumber: 29,
+		RedundantSizes:  []domain.PackSize{30},
+	}, analysis)
+
+	_, err = uc.Analyze(context.Background())
+	assert.ErrorIs(t, err, domain.ErrNoPackSizes)
+}
+
+func TestPackSizesUseCase_UpdateStock(t *testing.T) {
+	tests := []struct {
+		name    string
+		stock   []domain.PackStock
+		wantErr error
+		stored  []domain.PackStock
+	}{
+		{
+			name:   "valid entries are sorted",
+			stock:  []domain.PackStock{{Size: 500, Available: 2}, {Size: 250, Available: 0}},
+			stored: []domain.PackStock{{Size: 250, Available: 0}, {Size: 500, Available: 2}},
+		},
+		{
+			name:   "empty clears stock",
+			stock:  []domain.PackStock{},
+			stored: []domain.PackStock{},
+		},
+		{
+			name:    "negative availability",
+			stock:   []domain.PackStock{{Size: 250, Available: -1}},
+			wantErr: domain.ErrInvalidStock,
+		},
+		{
+			name:    "invalid size",
+			stock:   []domain.PackStock{{Size: 0, Available: 1}},
+			wantErr: domain.ErrInvalidStock,
+		},
+		{
+			name:    "duplicate size",
+			stock:   []domain.PackStock{{Size: 250, Available: 1}, {Size: 250, Available: 2}},
+			wantErr: domain.ErrInvalidStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdateStock(tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdateStock(tt.stock)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackSizesUseCase_UpdateCosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		costs   []domain.PackCost
+		wantErr error
+		stored  []domain.PackCost
+	}{
+		{
+			name:   "valid entries are sorted",
+			costs:  []domain.PackCost{{Size: 500, Cost: 90}, {Size: 250, Cost: 50}},
+			stored: []domain.PackCost{{Size: 250, Cost: 50}, {Size: 500, Cost: 90}},
+		},
+		{
+			name:    "zero cost",
+			costs:   []domain.PackCost{{Size: 250, Cost: 0}},
+			wantErr: domain.ErrInvalidPackCost,
+		},
+		{
+			name:    "exceeds max cost",
+			costs:   []domain.PackCost{{Size: 250, Cost: 1_000_000_001}},
+			wantErr: domain.ErrInvalidPackCost,
+		},
+		{
+			name:    "duplicate size",
+			costs:   []domain.PackCost{{Size: 250, Cost: 1}, {Size: 250, Cost: 2}},
+			wantErr: domain.ErrInvalidPackCost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdateCosts(tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdateCosts(tt.costs)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackSizesUseCase_UpdateConstraints(t *testing.T) {
+	one, three := 1, 3
+	tests := []struct {
+		name    string
+		current []domain.PackSize
+		input   []domain.PackConstraint
+		wantErr error
+		stored  []domain.PackConstraint
+	}{
+		{
+			name:    "valid entries are sorted",
+			current: []domain.PackSize{250, 500, 5000},
+			input:   []domain.PackConstraint{{Size: 5000, Min: 1, OrdersOver: 20000}, {Size: 250, Max: &three}},
+			stored:  []domain.PackConstraint{{Size: 250, Max: &three}, {Size: 5000, Min: 1, OrdersOver: 20000}},
+		},
+		{
+			name:    "thresholds may differ per order size",
+			current: []domain.PackSize{250, 500},
+			input:   []domain.PackConstraint{{Size: 250, Max: &one}, {Size: 250, Min: 3, OrdersOver: 1000}},
+			wantErr: domain.ErrUnsatisfiablePackConstraint,
+		},
+		{
+			name:    "every size capped",
+			current: []domain.PackSize{250, 500},
+			input:   []domain.PackConstraint{{Size: 250, Max: &three}, {Size: 500, Max: &one, OrdersOver: 1000}},
+			wantErr: domain.ErrUnsatisfiablePackConstraint,
+		},
+		{
+			name:    "caps on sizes outside the pack set",
+			current: []domain.PackSize{250},
+			input:   []domain.PackConstraint{{Size: 500, Max: &one}},
+			stored:  []domain.PackConstraint{{Size: 500, Max: &one}},
+		},
+		{
+			name:    "empty clears constraints",
+			current: []domain.PackSize{250},
+			input:   []domain.PackConstraint{},
+			stored:  []domain.PackConstraint{},
+		},
+		{
+			name:    "neither minimum nor maximum",
+			input:   []domain.PackConstraint{{Size: 250}},
+			wantErr: domain.ErrInvalidPackConstraint,
+		},
+		{
+			name:    "negative minimum",
+			input:   []domain.PackConstraint{{Size: 250, Min: -1}},
+			wantErr: domain.ErrInvalidPackConstraint,
+		},
+		{
+			name:    "threshold no order is over",
+			input:   []domain.PackConstraint{{Size: 250, Min: 1, OrdersOver: math.MaxInt64}},
+			wantErr: domain.ErrInvalidPackConstraint,
+		},
+		{
+			name:    "duplicate threshold",
+			input:   []domain.PackConstraint{{Size: 250, Min: 1}, {Size: 250, Max: &three}},
+			wantErr: domain.ErrInvalidPackConstraint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.current != nil {
+				mockRepo.EXPECT().GetPackSizes().Return(tt.current).AnyTimes()
+			}
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdateConstraints(tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdateConstraints(tt.input)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackSizesUseCase_UpdateDimensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		dimensions []domain.PackDimensions
+		wantErr    error
+		stored     []domain.PackDimensions
+	}{
+		{
+			name:       "valid entries are sorted",
+			dimensions: []domain.PackDimensions{{Size: 500, Weight: 500, Length: 20, Width: 10, Height: 10}, {Size: 250, Weight: 250, Length: 10, Width: 10, Height: 10}},
+			stored:     []domain.PackDimensions{{Size: 250, Weight: 250, Length: 10, Width: 10, Height: 10}, {Size: 500, Weight: 500, Length: 20, Width: 10, Height: 10}},
+		},
+		{
+			name:       "zero weight",
+			dimensions: []domain.PackDimensions{{Size: 250, Weight: 0, Length: 10, Width: 10, Height: 10}},
+			wantErr:    domain.ErrInvalidPackDimensions,
+		},
+		{
+			name:       "dimension too large",
+			dimensions: []domain.PackDimensions{{Size: 250, Weight: 250, Length: 10_001, Width: 10, Height: 10}},
+			wantErr:    domain.ErrInvalidPackDimensions,
+		},
+		{
+			name:       "duplicate size",
+			dimensions: []domain.PackDimensions{{Size: 250, Weight: 1, Length: 1, Width: 1, Height: 1}, {Size: 250, Weight: 2, Length: 1, Width: 1, Height: 1}},
+			wantErr:    domain.ErrInvalidPackDimensions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdateDimensions(tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdateDimensions(tt.dimensions)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackSizesUseCase_UpdateHierarchies(t *testing.T) {
+	casesOnPallets := []domain.PackagingLevel{{Name: "case", Contains: 12}, {Name: "pallet", Contains: 40}}
+	tests := []struct {
+		name        string
+		hierarchies []domain.PackHierarchy
+		wantErr     error
+		stored      []domain.PackHierarchy
+	}{
+		{
+			name:        "valid entries are sorted",
+			hierarchies: []domain.PackHierarchy{{Size: 500, Levels: casesOnPallets}, {Size: 250, Levels: casesOnPallets[:1]}},
+			stored:      []domain.PackHierarchy{{Size: 250, Levels: casesOnPallets[:1]}, {Size: 500, Levels: casesOnPallets}},
+		},
+		{
+			name:        "no levels",
+			hierarchies: []domain.PackHierarchy{{Size: 250}},
+			wantErr:     domain.ErrInvalidPackaging,
+		},
+		{
+			name:        "unit of one",
+			hierarchies: []domain.PackHierarchy{{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 1}}}},
+			wantErr:     domain.ErrInvalidPackaging,
+		},
+		{
+			name:        "repeated name",
+			hierarchies: []domain.PackHierarchy{{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 2}, {Name: "case", Contains: 2}}}},
+			wantErr:     domain.ErrInvalidPackaging,
+		},
+		{
+			name: "too many packs per unit",
+			hierarchies: []domain.PackHierarchy{{Size: 250, Levels: []domain.PackagingLevel{
+				{Name: "case", Contains: 1_000_000}, {Name: "pallet", Contains: 1_000_000}, {Name: "truck", Contains: 2},
+			}}},
+			wantErr: domain.ErrInvalidPackaging,
+		},
+		{
+			name:        "duplicate size",
+			hierarchies: []domain.PackHierarchy{{Size: 250, Levels: casesOnPallets}, {Size: 250, Levels: casesOnPallets}},
+			wantErr:     domain.ErrInvalidPackaging,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdateHierarchies(tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdateHierarchies(tt.hierarchies)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPackSizesUseCase_UpdatePricing(t *testing.T) {
+	price := func(size domain.PackSize, tiers ...domain.DiscountTier) domain.PackPrice {
+		return domain.PackPrice{Size: size, UnitPrice: 1500, Surcharge: 5000, DiscountTiers: tiers}
+	}
+	tests := []struct {
+		name    string
+		pricing domain.Pricing
+		wantErr error
+		stored  *domain.Pricing
+	}{
+		{
+			name: "prices and tiers are sorted",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+				price(500, domain.DiscountTier{MinPacks: 100, Percent: 100_000}, domain.DiscountTier{MinPacks: 10, Percent: 50_000}),
+				price(250),
+			}},
+			stored: &domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+				price(250, []domain.DiscountTier{}...),
+				price(500, domain.DiscountTier{MinPacks: 10, Percent: 50_000}, domain.DiscountTier{MinPacks: 100, Percent: 100_000}),
+			}},
+		},
+		{
+			name:    "no prices",
+			pricing: domain.Pricing{Currency: "USD"},
+			stored:  &domain.Pricing{Currency: "USD", Prices: []domain.PackPrice{}},
+		},
+		{
+			name:    "invalid currency",
+			pricing: domain.Pricing{Currency: "euro", Prices: []domain.PackPrice{price(250)}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name:    "negative price",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{{Size: 250, UnitPrice: -1}}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name:    "surcharge too large",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{{Size: 250, Surcharge: (maxPrice + 1) * domain.DecimalScale}}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name:    "duplicate size",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{price(250), price(250)}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name:    "discount over 100 percent",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{price(250, domain.DiscountTier{MinPacks: 1, Percent: 1_000_001})}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name:    "tier without packs",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{price(250, domain.DiscountTier{Percent: 10_000})}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+		{
+			name: "tiers at the same count",
+			pricing: domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+				price(250, domain.DiscountTier{MinPacks: 5, Percent: 10_000}, domain.DiscountTier{MinPacks: 5, Percent: 20_000}),
+			}},
+			wantErr: domain.ErrInvalidPricing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().UpdatePricing(*tt.stored).Return(nil)
+			}
+
+			uc := NewPackSizesUseCase(mockRepo, NewPackTableCache())
+			err := uc.UpdatePricing(tt.pricing)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"container/list"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// maxDenseEntries caps the per-amount table kept for amounts below the point
+// where the residue tables become exact (16 MiB of int32).
+const maxDenseEntries = 1 << 22
+
+// packTable is the precomputed solver state for one pack set. It is immutable
+// once built, so concurrent calculations can read it without locking.
+//
+// Memory does not depend on the product of pack sizes: two residue tables of
+// 17 bytes per class (minPack + maxPack classes, ~34 MiB at the 1,000,000
+// limit) plus at most maxDenseEntries int32s. Building takes
+// O(k*(minPack+maxPack) + k*maxDenseEntries) for k pack sizes. Orders beyond
+// the stored amounts, up to the int64 limit, are answered from tail alone in
+// O(minPack) time and O(k) extra memory.
+type packTable struct {
+	version uint64
+	sizes   []int // ascending
+
+	// unit is the GCD of the sizes. Only its multiples can be shipped, so the
+	// tables below count in units of it and work on units, the sizes divided
+	// by it: 250/500/1000 costs no more than 1/2/4.
+	unit  int64
+	units []int
+
+	// reach holds the smallest shippable amount per class modulo the smallest
+	// pack; it decides which totals can be shipped at all.
+	reach *residueTable
+	// tail holds the fewest-pack combination of the smaller sizes per class
+	// modulo the largest pack. Topped up with largest packs it is optimal for
+	// every total at or above the stored amount.
+	tail *residueTable
+	// dense holds the fewest packs for amounts below tail's largest stored
+	// amount, for the totals tail cannot answer on its own.
+	dense []int32
+}
+
+func newPackTable(ctx context.Context, sizes []int) (*packTable, error) {
+	unit := sizes[0]
+	for _, size := range sizes[1:] {
+		unit = gcd(unit, size)
+	}
+	units := make([]int, len(sizes))
+	for i, size := range sizes {
+		units[i] = size / unit
+	}
+	minPack := units[0]
+	maxPack := units[len(units)-1]
+
+	reach, err := newResidueTable(ctx, minPack, units[1:], func(pack int) int64 { return int64(pack) })
+	if err != nil {
+		return nil, err
+	}
+	// Each smaller pack costs the items it falls short of a largest pack, so
+	// the cheapest class combination needs the fewest packs in total.
+	tail, err := newResidueTable(ctx, maxPack, units[:len(units)-1], func(pack int) int64 { return int64(maxPack - pack) })
+	if err != nil {
+		return nil, err
+	}
+
+	t := &packTable{sizes: sizes, unit: int64(unit), units: units, reach: reach, tail: tail}
+	if n := int(min(tail.maxAmount(), maxDenseEntries)); n > 0 {
+		if t.dense, err = fillDensePacks(ctx, units, n); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// fillDensePacks computes the fewest packs for every amount below n, or -1
+// where the amount cannot be made of whole packs.
+func fillDensePacks(ctx context.Context, sizes []int, n int) ([]int32, error) {
+	dp := make([]int32, n)
+	for i := 1; i < n; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := contextError(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		dp[i] = -1
+		for _, pack := range sizes {
+			if pack > i {
+				break
+			}
+			if prev := dp[i-pack]; prev >= 0 && (dp[i] < 0 || prev+1 < dp[i]) {
+				dp[i] = prev + 1
+			}
+		}
+	}
+	return dp, nil
+}
+
+// analysis reads the GCD, Frobenius number and redundant sizes of the pack set
+// off the reach table.
+func (t *packTable) analysis() *domain.PackSetAnalysis {
+	a := &domain.PackSetAnalysis{
+		PackSizes:       make([]domain.PackSize, len(t.sizes)),
+		GCD:             int(t.unit),
+		FrobeniusNumber: -1,
+		RedundantSizes:  []domain.PackSize{},
+	}
+	for i, size := range t.sizes {
+		a.PackSizes[i] = domain.PackSize(size)
+	}
+
+	// The units are coprime, so every class is reachable from its stored
+	// amount on, and the largest amount missed lies one smallest pack below
+	// the largest stored one.
+	if f := t.reach.maxAmount() - int64(t.reach.modulus); f > 0 {
+		a.FrobeniusNumber = f * t.unit
+	}
+	for i, covered := range t.reach.covered {
+		if covered {
+			a.RedundantSizes = append(a.RedundantSizes, domain.PackSize(t.sizes[i+1]))
+		}
+	}
+	return a
+}
+
+// smallestTotal returns the fewest items that whole packs can ship for the
+// order, or false if that total does not fit in an int64. It scans at most one
+// class per smallest-pack residue.
+func (t *packTable) smallestTotal(orderSize int64) (int64, bool) {
+	n := ceilDiv(orderSize, t.unit)
+	limit := math.MaxInt64 / t.unit // the most units whose items fit
+	if n > limit {
+		return 0, false
+	}
+
+	minPack := int64(t.reach.modulus)
+	for x := n; x-n < minPack; x++ {
+		if t.reach.reachable(x) {
+			return x * t.unit, true
+		}
+		if x == limit {
+			return 0, false
+		}
+	}
+
+	// Every class is still below its smallest shippable amount; the answer is
+	// the smallest of those amounts. Class 0 always exists, so one is found.
+	best := int64(-1)
+	for r, amount := range t.reach.amount {
+		if t.reach.weight[r] >= 0 && amount >= n && (best < 0 || amount < best) {
+			best = amount
+		}
+	}
+	return best * t.unit, true
+}
+
+// largestTotalBelow returns the most items whole packs can ship that are
+// still fewer than orderSize, or 0 if there is no such total. Like
+// smallestTotal it looks at one amount per smallest-pack residue.
+func (t *packTable) largestTotalBelow(orderSize int64) int64 {
+	// Totals below the order are the multiples of unit below n units.
+	n := ceilDiv(orderSize, t.unit)
+	minPack := int64(t.reach.modulus)
+	best := int64(0)
+	for r, amount := range t.reach.amount {
+		if t.reach.weight[r] < 0 || n-1 < int64(r) {
+			continue
+		}
+		// The largest amount of class r below the order.
+		x := n - 1 - (n-1-int64(r))%minPack
+		if x >= amount && x > best {
+			best = x
+		}
+	}
+	return best * t.unit
+}
+
+// compose returns the fewest-pack combination for a shippable total, keyed by
+// pack size, and whether the total lies beyond the amounts the tables store,
+// so the largest packs were pre-allocated.
+func (t *packTable) compose(ctx context.Context, total int64) (map[int]int64, bool, error) {
+	counts, largeOrder, err := t.composeUnits(ctx, total/t.unit)
+	if err != nil || t.unit == 1 {
+		return counts, largeOrder, err
+	}
+
+	scaled := make(map[int]int64, len(counts))
+	for units, n := range counts {
+		scaled[units*int(t.unit)] = n
+	}
+	return scaled, largeOrder, nil
+}
+
+// composeUnits is compose for a total counted in units, with the counts keyed
+// by units.
+func (t *packTable) composeUnits(ctx context.Context, total int64) (counts map[int]int64, largeOrder bool, err error) {
+	maxPack := t.tail.modulus
+	counts = make(map[int]int64)
+
+	switch {
+	case t.tail.reachable(total):
+		r := int(total % int64(maxPack))
+		large := (total - t.tail.amount[r]) / int64(maxPack)
+		if large > 0 {
+			counts[maxPack] = large
+		}
+		t.tail.addCombination(r, counts)
+		// Past every stored amount by a largest pack, each class takes some
+		// largest packs up front and only the rest comes from the table.
+		return counts, total >= t.tail.maxAmount()+int64(maxPack), nil
+	case total < int64(len(t.dense)):
+		for remaining := int(total); remaining > 0; {
+			for _, pack := range t.units {
+				if pack <= remaining && t.dense[remaining-pack] == t.dense[remaining]-1 {
+					counts[pack]++
+					remaining -= pack
+					break
+				}
+			}
+		}
+		return counts, false, nil
+	default:
+		counts, err = t.composeWindowed(ctx, total)
+		return counts, false, err
+	}
+}
+
+// composeWindowed solves totals that fall between the dense table and the
+// point where tail becomes exact. That only happens for pack sets whose tail
+// combinations exceed maxDenseEntries items, and only when the order is
+// smaller than its class's combination.
+//
+// It runs the same DP as fillDensePacks, but keeps just the last maxPack+1
+// amounts together with their pack counts: O(k*maxPack) memory and
+// O(k*total) time. Optimal counts are bounded by k*maxPack (a multiset of
+// maxPack smaller packs always contains a subset worth a whole number of
+// largest packs), so int32 is enough.
+func (t *packTable) composeWindowed(ctx context.Context, total int64) (map[int]int64, error) {
+	k := int64(len(t.units))
+	width := int64(t.units[k-1] + 1)
+
+	packs := make([]int32, width)
+	rows := make([]int32, width*k)
+
+	for i := int64(1); i <= total; i++ {
+		if i%cancelCheckInterval == 0 {
+			if err := contextError(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		slot := i % width
+		row := rows[slot*k : slot*k+k]
+		packs[slot] = -1
+		for j, pack := range t.units {
+			if int64(pack) > i {
+				break
+			}
+			prev := (i - int64(pack)) % width
+			if packs[prev] >= 0 && (packs[slot] < 0 || packs[prev]+1 < packs[slot]) {
+				packs[slot] = packs[prev] + 1
+				copy(row, rows[prev*k:prev*k+k])
+				row[j]++
+			}
+		}
+	}
+
+	counts := make(map[int]int64)
+	slot := total % width
+	for j, n := range rows[slot*k : slot*k+k] {
+		if n > 0 {
+			counts[t.units[j]] = int64(n)
+		}
+	}
+	return counts, nil
+}
+
+// PackTableCache holds the DP table of the current pack set. The table is
+// rebuilt only when the pack set changes and is swapped in atomically, so
+// readers always see a complete table for a single pack set.
+type PackTableCache struct {
+	mu      sync.Mutex
+	current atomic.Pointer[packTable]
+	version uint64
+}
+
+func NewPackTableCache() *PackTableCache {
+	return &PackTableCache{}
+}
+
+// get returns the table for the given pack set, building it on first use. A
+// build cut short by ctx is discarded, so the next caller starts over.
+func (c *PackTableCache) get(ctx context.Context, packSizes []domain.PackSize) (*packTable, error) {
+	sizes := normalizePackSizes(packSizes)
+	if t := c.current.Load(); t != nil && slices.Equal(t.sizes, sizes) {
+		return t, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have built it while we were waiting for the lock.
+	if t := c.current.Load(); t != nil && slices.Equal(t.sizes, sizes) {
+		return t, nil
+	}
+	return c.store(ctx, sizes)
+}
+
+// warm builds the table for a freshly stored pack set so the next calculation
+// does not pay for it.
+func (c *PackTableCache) warm(packSizes []domain.PackSize) {
+	sizes := normalizePackSizes(packSizes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t := c.current.Load(); t != nil && slices.Equal(t.sizes, sizes) {
+		return
+	}
+	// Without a deadline the build cannot fail.
+	_, _ = c.store(context.Background(), sizes)
+}
+
+// store must be called with c.mu held.
+func (c *PackTableCache) store(ctx context.Context, sizes []int) (*packTable, error) {
+	t, err := newPackTable(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+	c.version++
+	t.version = c.version
+	c.current.Store(t)
+	return t, nil
+}
+
+// PackTablesByID holds a PackTableCache per product or warehouse ID, keeping
+// the most recently used up to a fixed number. A table can take tens of MiB,
+// so the owner of the pack sets evicts an ID whenever its pack set changes or
+// goes away. It is safe for concurrent use.
+type PackTablesByID struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // of *idTables, front is the most recently used
+}
+
+type idTables struct {
+	id     string
+	tables *PackTableCache
+}
+
+// NewPackTablesByID returns a holder of up to capacity table caches, at least
+// one.
+func NewPackTablesByID(capacity int) *PackTablesByID {
+	capacity = max(capacity, 1)
+	return &PackTablesByID{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns the table cache of id, creating it on first use and dropping
+// the least recently used beyond the capacity. A dropped cache stays usable
+// by calculations that already hold it.
+func (c *PackTablesByID) get(id string) *PackTableCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		if e, ok := el.Value.(*idTables); ok {
+			c.order.MoveToFront(el)
+			return e.tables
+		}
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		if e, ok := oldest.Value.(*idTables); ok {
+			delete(c.entries, e.id)
+		}
+	}
+	e := &idTables{id: id, tables: NewPackTableCache()}
+	c.entries[id] = c.order.PushFront(e)
+	return e.tables
+}
+
+// evict drops the table cache of id, if any.
+func (c *PackTablesByID) evict(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		c.order.Remove(el)
+		delete(c.entries, id)
+	}
+}
+
+// len reports how many table caches are held.
+func (c *PackTablesByID) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func normalizePackSizes(packSizes []domain.PackSize) []int {
+	sizes := make([]int, 0, len(packSizes))
+	for _, ps := range packSizes {
+		sizes = append(sizes, int(ps))
+	}
+	slices.Sort(sizes)
+	return slices.Compact(sizes)
+}
@@ -0,0 +1,312 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackTableCache_ReusesTableForSamePackSet(t *testing.T) {
+	cache := NewPackTableCache()
+
+	first := mustGet(t, cache, []domain.PackSize{250, 500, 1000})
+	second := mustGet(t, cache, []domain.PackSize{1000, 250, 500})
+
+	assert.Same(t, first, second)
+	assert.Equal(t, []int{250, 500, 1000}, first.sizes)
+}
+
+func TestPackTableCache_RebuildsOnChange(t *testing.T) {
+	cache := NewPackTableCache()
+
+	first := mustGet(t, cache, []domain.PackSize{250, 500})
+	second := mustGet(t, cache, []domain.PackSize{23, 31, 53})
+
+	assert.NotSame(t, first, second)
+	assert.Greater(t, second.version, first.version)
+	assert.Equal(t, []int{23, 31, 53}, second.sizes)
+}
+
+func TestPackTableCache_Warm(t *testing.T) {
+	cache := NewPackTableCache()
+
+	cache.warm([]domain.PackSize{250, 500})
+	warmed := cache.current.Load()
+	require.NotNil(t, warmed)
+
+	assert.Same(t, warmed, mustGet(t, cache, []domain.PackSize{250, 500}))
+
+	// Warming the same set again keeps the existing table.
+	cache.warm([]domain.PackSize{500, 250})
+	assert.Same(t, warmed, cache.current.Load())
+}
+
+func TestPackTableCache_ConcurrentAccess(t *testing.T) {
+	cache := NewPackTableCache()
+	sets := [][]domain.PackSize{{250, 500, 1000}, {23, 31, 53}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(set []domain.PackSize) {
+			defer wg.Done()
+			table, err := cache.get(context.Background(), set)
+			assert.NoError(t, err)
+			assert.Len(t, table.sizes, len(set))
+		}(sets[i%2])
+		go func(set []domain.PackSize) {
+			defer wg.Done()
+			cache.warm(set)
+		}(sets[(i+1)%2])
+	}
+	wg.Wait()
+}
+
+func TestPackTablesByID_EvictsLeastRecentlyUsed(t *testing.T) {
+	tables := NewPackTablesByID(2)
+
+	a := tables.get("a")
+	b := tables.get("b")
+	assert.Same(t, a, tables.get("a"))
+
+	// "b" is the least recently used.
+	tables.get("c")
+	assert.Equal(t, 2, tables.len())
+	assert.Same(t, a, tables.get("a"))
+	assert.NotSame(t, b, tables.get("b"))
+}
+
+func TestPackTablesByID_Evict(t *testing.T) {
+	tables := NewPackTablesByID(2)
+
+	a := tables.get("a")
+	tables.evict("a")
+	tables.evict("missing")
+	assert.Equal(t, 0, tables.len())
+	assert.NotSame(t, a, tables.get("a"))
+}
+
+func mustPackTable(t testing.TB, sizes ...int) *packTable {
+	t.Helper()
+	table, err := newPackTable(context.Background(), sizes)
+	require.NoError(t, err)
+	return table
+}
+
+func mustGet(t *testing.T, cache *PackTableCache, sizes []domain.PackSize) *packTable {
+	t.Helper()
+	table, err := cache.get(context.Background(), sizes)
+	require.NoError(t, err)
+	return table
+}
+
+func mustOptimalPacks(t *testing.T, orderSize int64, table *packTable) map[int]int64 {
+	t.Helper()
+	result, err := calculateOptimalPacks(context.Background(), orderSize, table)
+	require.NoError(t, err)
+	return result
+}
+
+// naiveOptimum runs the plain DP over every amount up to orderSize+maxPack and
+// returns the fewest items and, for those, the fewest packs.
+func naiveOptimum(orderSize int64, sizes []int) (total, packs int64) {
+	limit := orderSize + int64(sizes[len(sizes)-1])
+	dp, _ := fillDensePacks(context.Background(), sizes, int(limit)+1)
+	for t := orderSize; t <= limit; t++ {
+		if dp[t] >= 0 {
+			return t, int64(dp[t])
+		}
+	}
+	return -1, -1
+}
+
+func sumPacks(counts map[int]int64) (total, packs int64) {
+	for size, n := range counts {
+		total += int64(size) * n
+		packs += n
+	}
+	return total, packs
+}
+
+func TestCalculateOptimalPacks_MatchesNaiveDP(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		sizes := make([]int, 1+rng.Intn(4))
+		for j := range sizes {
+			sizes[j] = 1 + rng.Intn(60)
+		}
+		table := mustPackTable(t, slices.Compact(slices.Sorted(slices.Values(sizes)))...)
+
+		for orderSize := int64(1); orderSize <= 400; orderSize += 1 + rng.Int63n(7) {
+			wantTotal, wantPacks := naiveOptimum(orderSize, table.sizes)
+			gotTotal, gotPacks := sumPacks(mustOptimalPacks(t, orderSize, table))
+
+			require.Equal(t, wantTotal, gotTotal, "sizes %v, order %d", table.sizes, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "sizes %v, order %d", table.sizes, orderSize)
+		}
+	}
+}
+
+func TestCalculateOptimalPacks_CommonDivisor(t *testing.T) {
+	table := mustPackTable(t, 250, 500, 1000, 2000, 5000)
+	assert.Equal(t, int64(250), table.unit)
+	assert.Equal(t, []int{1, 2, 4, 8, 20}, table.units)
+	assert.Equal(t, 1, table.reach.modulus)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		scale := 2 + rng.Intn(6)
+		sizes := make([]int, 1+rng.Intn(4))
+		for j := range sizes {
+			sizes[j] = scale * (1 + rng.Intn(20))
+		}
+		table := mustPackTable(t, slices.Compact(slices.Sorted(slices.Values(sizes)))...)
+
+		for orderSize := int64(1); orderSize <= 400; orderSize += 1 + rng.Int63n(7) {
+			wantTotal, wantPacks := naiveOptimum(orderSize, table.sizes)
+			gotTotal, gotPacks := sumPacks(mustOptimalPacks(t, orderSize, table))
+
+			require.Equal(t, wantTotal, gotTotal, "sizes %v, order %d", table.sizes, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "sizes %v, order %d", table.sizes, orderSize)
+		}
+	}
+}
+
+func TestPackTable_Analysis(t *testing.T) {
+	tests := []struct {
+		sizes []int
+		want  domain.PackSetAnalysis
+	}{
+		{
+			sizes: []int{250, 500, 1000, 2000, 5000},
+			want:  domain.PackSetAnalysis{GCD: 250, FrobeniusNumber: -1, RedundantSizes: []domain.PackSize{500, 1000, 2000, 5000}},
+		},
+		{
+			sizes: []int{3, 5},
+			want:  domain.PackSetAnalysis{GCD: 1, FrobeniusNumber: 7, RedundantSizes: []domain.PackSize{}},
+		},
+		{
+			sizes: []int{6, 10, 15, 30},
+			want:  domain.PackSetAnalysis{GCD: 1, FrobeniusNumber: 29, RedundantSizes: []domain.PackSize{30}},
+		},
+		{
+			// 12 and 30 in units of 6 are 2 and 5, whose Frobenius number is 3.
+			sizes: []int{12, 30, 42},
+			want:  domain.PackSetAnalysis{GCD: 6, FrobeniusNumber: 18, RedundantSizes: []domain.PackSize{42}},
+		},
+	}
+	for _, tt := range tests {
+		got := mustPackTable(t, tt.sizes...).analysis()
+		tt.want.PackSizes = make([]domain.PackSize, len(tt.sizes))
+		for i, size := range tt.sizes {
+			tt.want.PackSizes[i] = domain.PackSize(size)
+		}
+		assert.Equal(t, &tt.want, got, "sizes %v", tt.sizes)
+	}
+}
+
+func TestPackTable_RedundantSizesKeepFewestItems(t *testing.T) {
+	for _, sizes := range [][]int{{250, 500, 1000, 2000, 5000}, {6, 10, 15, 30}, {12, 30, 42}, {4, 6, 9, 13}} {
+		table := mustPackTable(t, sizes...)
+		for _, redundant := range table.analysis().RedundantSizes {
+			without := mustPackTable(t, slices.DeleteFunc(slices.Clone(sizes), func(s int) bool { return s == int(redundant) })...)
+			for orderSize := int64(1); orderSize <= 20_000; orderSize++ {
+				want, _ := table.smallestTotal(orderSize)
+				got, _ := without.smallestTotal(orderSize)
+				require.Equal(t, want, got, "sizes %v without %d, order %d", sizes, redundant, orderSize)
+			}
+		}
+	}
+}
+
+func TestCalculateOptimalPacks_HugePackSizes(t *testing.T) {
+	table := mustPackTable(t, 999983, 1000000)
+
+	tests := []struct {
+		orderSize int64
+		want      map[int]int64
+	}{
+		{orderSize: 1, want: map[int]int64{999983: 1}},
+		{orderSize: 999984, want: map[int]int64{1000000: 1}},
+		{orderSize: 2_000_000, want: map[int]int64{1000000: 2}},
+		{orderSize: 500_000_000_000, want: map[int]int64{1000000: 500_000}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, mustOptimalPacks(t, tt.orderSize, table), "order %d", tt.orderSize)
+	}
+
+	// Between the exact cases every total must be a shippable combination at
+	// or above the order.
+	for _, orderSize := range []int64{123_456_789, 987_654_321_123} {
+		total, _ := sumPacks(mustOptimalPacks(t, orderSize, table))
+		assert.GreaterOrEqual(t, total, orderSize)
+		assert.Less(t, total, orderSize+999983)
+	}
+}
+
+func TestPackTable_ComposeWindowed(t *testing.T) {
+	sizes := []int{23, 31, 53}
+	full := mustPackTable(t, sizes...)
+
+	// Drop the dense table so totals below tail's stored amounts take the
+	// windowed path.
+	windowed := mustPackTable(t, sizes...)
+	windowed.dense = nil
+
+	for orderSize := int64(1); orderSize <= 2000; orderSize++ {
+		total, _ := full.smallestTotal(orderSize)
+		want, _, err := full.compose(context.Background(), total)
+		require.NoError(t, err)
+		got, _, err := windowed.compose(context.Background(), total)
+		require.NoError(t, err)
+
+		_, wantPacks := sumPacks(want)
+		gotTotal, gotPacks := sumPacks(got)
+		require.Equal(t, total, gotTotal, "order %d", orderSize)
+		require.Equal(t, wantPacks, gotPacks, "order %d", orderSize)
+	}
+}
+
+func TestResidueTable_Reach(t *testing.T) {
+	// With packs of 3 and 5 every amount above 7 can be shipped.
+	table := mustPackTable(t, 3, 5)
+
+	for _, amount := range []int64{1, 2, 4, 7} {
+		assert.False(t, table.reach.reachable(amount), "amount %d", amount)
+	}
+	for amount := int64(8); amount < 30; amount++ {
+		assert.True(t, table.reach.reachable(amount), "amount %d", amount)
+	}
+	assert.Equal(t, int64(10), table.reach.maxAmount())
+}
+
+func TestPackTable_LargestTotalBelow(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		sizes := make([]int, 1+rng.Intn(3))
+		for j := range sizes {
+			sizes[j] = 1 + rng.Intn(40)
+		}
+		table := mustPackTable(t, slices.Compact(slices.Sorted(slices.Values(sizes)))...)
+		dp, _ := fillDensePacks(context.Background(), table.sizes, 300)
+
+		for orderSize := int64(1); orderSize < 300; orderSize++ {
+			want := int64(0)
+			for x := orderSize - 1; x > 0; x-- {
+				if dp[x] >= 0 {
+					want = x
+					break
+				}
+			}
+			require.Equal(t, want, table.largestTotalBelow(orderSize), "sizes %v, order %d", table.sizes, orderSize)
+		}
+	}
+}
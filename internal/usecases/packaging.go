@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+)
+
+// PlanPackaging packs the order like Execute and consolidates the packs of
+// every size into its packaging hierarchy. Sizes without one ship loose.
+func (uc *CalculatePacksUseCase) PlanPackaging(
+	ctx context.Context,
+	orderSize int64,
+	opts domain.CalculateOptions,
+) (*domain.PackagingPlan, error) {
+	packs, err := uc.Execute(ctx, orderSize, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PackagingPlan{Packs: packs, Consolidation: consolidate(packs, uc.repo.GetHierarchies())}, nil
+}
+
+// consolidate fills the largest units first, so that only the remainder of
+// each level drops to the one below. packs are ordered from the largest size
+// down, and so is the result.
+func consolidate(packs []domain.PackResult, hierarchies []domain.PackHierarchy) []domain.Consolidation {
+	bySize := make(map[domain.PackSize][]domain.PackagingLevel, len(hierarchies))
+	for _, h := range hierarchies {
+		bySize[h.Size] = h.Levels
+	}
+
+	result := make([]domain.Consolidation, 0, len(packs))
+	for _, p := range packs {
+		levels := bySize[p.Size]
+		c := domain.Consolidation{Size: p.Size, Packs: p.Count, Units: make([]domain.PackagingUnit, len(levels))}
+
+		// per[i] is the packs in one unit of level i.
+		per := make([]int64, len(levels))
+		for i, level := range levels {
+			per[i] = level.Contains
+			if i > 0 {
+				per[i] *= per[i-1]
+			}
+		}
+
+		left := p.Count
+		for i := len(levels) - 1; i >= 0; i-- {
+			c.Units[len(levels)-1-i] = domain.PackagingUnit{Name: levels[i].Name, Count: left / per[i]}
+			left %= per[i]
+		}
+		c.LoosePacks = left
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalculatePacksUseCase_PlanPackaging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 5000})
+	mockRepo.EXPECT().GetConstraints().Return(nil)
+	mockRepo.EXPECT().GetHierarchies().Return([]domain.PackHierarchy{
+		{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 12}, {Name: "pallet", Contains: 40}}},
+	})
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	plan, err := useCase.PlanPackaging(context.Background(), 5250, domain.CalculateOptions{Policy: "fewest-packs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.PackagingPlan{
+		Packs: []domain.PackResult{{Size: 5000, Count: 1}, {Size: 250, Count: 1}},
+		Consolidation: []domain.Consolidation{
+			{Size: 5000, Packs: 1, Units: []domain.PackagingUnit{}, LoosePacks: 1},
+			{Size: 250, Packs: 1, Units: []domain.PackagingUnit{{Name: "pallet", Count: 0}, {Name: "case", Count: 0}}, LoosePacks: 1},
+		},
+	}, plan)
+}
+
+func TestConsolidate(t *testing.T) {
+	hierarchies := []domain.PackHierarchy{
+		{Size: 250, Levels: []domain.PackagingLevel{{Name: "case", Contains: 12}, {Name: "pallet", Contains: 40}}},
+		{Size: 500, Levels: []domain.PackagingLevel{{Name: "carton", Contains: 6}}},
+	}
+	packs := []domain.PackResult{{Size: 1000, Count: 7}, {Size: 500, Count: 13}, {Size: 250, Count: 2*480 + 3*12 + 5}}
+
+	assert.Equal(t, []domain.Consolidation{
+		{Size: 1000, Packs: 7, Units: []domain.PackagingUnit{}, LoosePacks: 7},
+		{Size: 500, Packs: 13, Units: []domain.PackagingUnit{{Name: "carton", Count: 2}}, LoosePacks: 1},
+		{Size: 250, Packs: 1001, Units: []domain.PackagingUnit{{Name: "pallet", Count: 2}, {Name: "case", Count: 3}}, LoosePacks: 5},
+	}, consolidate(packs, hierarchies))
+}
@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// Policy ranks the packings that cover an order. The solver finds the best
+// packing of every total from the order up, which is the one with the fewest
+// packs (the cheapest, then the fewest packs, for a CostAware policy), and
+// asks the policy to pick among them.
+//
+// Dropping a pack from a packing must never make it rank worse as long as it
+// still covers the order. That keeps the optimum below orderSize plus the
+// largest pack, which is all the solver searches.
+type Policy interface {
+	// Name identifies the policy in requests and explanations.
+	Name() string
+	// Less reports whether a should be shipped rather than b. Both cover
+	// orderSize.
+	Less(orderSize int64, a, b Candidate) bool
+}
+
+// CostAware is implemented by policies that rank packings by pack cost. The
+// solver then fills in Candidate.Cost from the stored pack costs.
+type CostAware interface {
+	Policy
+	UsesCosts() bool
+}
+
+// Candidate is the best packing of one total, as seen by a Policy.
+type Candidate struct {
+	Total int64
+	Packs int64
+	Cost  int64 // zero unless the policy is CostAware
+}
+
+// FewestItems ships the fewest items, then uses the fewest packs. It is the
+// default policy and the only one the residue tables answer directly.
+type FewestItems struct{}
+
+func (FewestItems) Name() string { return "fewest-items" }
+
+func (FewestItems) Less(_ int64, a, b Candidate) bool {
+	return a.Total < b.Total || (a.Total == b.Total && a.Packs < b.Packs)
+}
+
+// FewestPacks uses the fewest packs, then ships the fewest items.
+type FewestPacks struct{}
+
+func (FewestPacks) Name() string { return "fewest-packs" }
+
+func (FewestPacks) Less(_ int64, a, b Candidate) bool {
+	return a.Packs < b.Packs || (a.Packs == b.Packs && a.Total < b.Total)
+}
+
+// LargerPacks ranks like FewestItems but always breaks remaining ties towards
+// the larger packs, which the bounded search guarantees by construction.
+type LargerPacks struct{ FewestItems }
+
+func (LargerPacks) Name() string { return "larger-packs" }
+
+// LowestCost ships the cheapest packing, then the fewest items, then the
+// fewest packs.
+type LowestCost struct{}
+
+func (LowestCost) Name() string { return "lowest-cost" }
+
+func (LowestCost) Less(_ int64, a, b Candidate) bool {
+	if a.Cost != b.Cost {
+		return a.Cost < b.Cost
+	}
+	return FewestItems{}.Less(0, a, b)
+}
+
+func (LowestCost) UsesCosts() bool { return true }
+
+// BoundedOvershoot uses the fewest packs among the packings that overshoot
+// the order by at most Percent percent, then ships the fewest items. When no
+// packing stays within the bound it falls back to FewestItems.
+type BoundedOvershoot struct {
+	Percent int
+}
+
+func (p BoundedOvershoot) Name() string {
+	return "bounded-overshoot:" + strconv.Itoa(p.Percent)
+}
+
+func (p BoundedOvershoot) Less(orderSize int64, a, b Candidate) bool {
+	limit := saturatingAdd(orderSize, percentOf(orderSize, p.Percent))
+	aWithin, bWithin := a.Total <= limit, b.Total <= limit
+	switch {
+	case aWithin != bWithin:
+		return aWithin
+	case aWithin:
+		return FewestPacks{}.Less(orderSize, a, b)
+	default:
+		return FewestItems{}.Less(orderSize, a, b)
+	}
+}
+
+// maxOvershootPercent bounds the percentages BoundedOvershoot and the
+// overshoot caps accept.
+const maxOvershootPercent = 1000
+
+// ParsePolicy returns the policy with the given name. BoundedOvershoot takes
+// its percentage after a colon, as in "bounded-overshoot:10".
+func ParsePolicy(name string) (Policy, error) {
+	switch name {
+	case FewestItems{}.Name():
+		return FewestItems{}, nil
+	case FewestPacks{}.Name():
+		return FewestPacks{}, nil
+	case LargerPacks{}.Name():
+		return LargerPacks{}, nil
+	case LowestCost{}.Name():
+		return LowestCost{}, nil
+	}
+
+	if v, ok := strings.CutPrefix(name, "bounded-overshoot:"); ok {
+		percent, err := strconv.Atoi(v)
+		if err == nil && percent >= 0 && percent <= maxOvershootPercent {
+			return BoundedOvershoot{Percent: percent}, nil
+		}
+	}
+	return nil, domain.ErrInvalidPolicy
+}
+
+// usesCosts reports whether the policy needs pack costs.
+func usesCosts(p Policy) bool {
+	c, ok := p.(CostAware)
+	return ok && c.UsesCosts()
+}
+
+// itemsFirst reports whether the policy ranks by items, then packs, so the
+// rules of domain.Calculation.DecidedBy apply to it.
+func itemsFirst(p Policy) bool {
+	switch p.(type) {
+	case FewestItems, LargerPacks:
+		return true
+	}
+	return false
+}
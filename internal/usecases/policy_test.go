@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		want Policy
+	}{
+		{name: "fewest-items", want: FewestItems{}},
+		{name: "fewest-packs", want: FewestPacks{}},
+		{name: "larger-packs", want: LargerPacks{}},
+		{name: "lowest-cost", want: LowestCost{}},
+		{name: "bounded-overshoot:15", want: BoundedOvershoot{Percent: 15}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePolicy(tt.name)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got)
+		assert.Equal(t, tt.name, got.Name())
+	}
+
+	for _, name := range []string{"", "cheapest", "bounded-overshoot", "bounded-overshoot:-1", "bounded-overshoot:1001"} {
+		_, err := ParsePolicy(name)
+		assert.ErrorIs(t, err, domain.ErrInvalidPolicy, name)
+	}
+}
+
+func TestBoundedOvershoot_Less(t *testing.T) {
+	p := BoundedOvershoot{Percent: 10}
+
+	// Within 10% of 1000 the fewer packs win, even with more items.
+	assert.True(t, p.Less(1000, Candidate{Total: 1100, Packs: 1}, Candidate{Total: 1000, Packs: 4}))
+	// Anything within the bound beats anything beyond it.
+	assert.True(t, p.Less(1000, Candidate{Total: 1100, Packs: 4}, Candidate{Total: 1101, Packs: 1}))
+	// Beyond it the fewer items win.
+	assert.True(t, p.Less(1000, Candidate{Total: 1200, Packs: 4}, Candidate{Total: 1300, Packs: 1}))
+}
+
+func TestCalculatePacksUseCase_Policies(t *testing.T) {
+	tests := []struct {
+		name      string
+		packSizes []domain.PackSize
+		orderSize int64
+		policy    string
+		expected  []domain.PackResult
+	}{
+		{
+			name:      "fewest items",
+			packSizes: []domain.PackSize{250, 5000},
+			orderSize: 4750,
+			policy:    "fewest-items",
+			expected:  []domain.PackResult{{Size: 250, Count: 19}},
+		},
+		{
+			name:      "fewest packs",
+			packSizes: []domain.PackSize{250, 5000},
+			orderSize: 4750,
+			policy:    "fewest-packs",
+			expected:  []domain.PackResult{{Size: 5000, Count: 1}},
+		},
+		{
+			name:      "overshoot within bound",
+			packSizes: []domain.PackSize{250, 5000},
+			orderSize: 4750,
+			policy:    "bounded-overshoot:10",
+			expected:  []domain.PackResult{{Size: 5000, Count: 1}},
+		},
+		{
+			name:      "overshoot beyond bound",
+			packSizes: []domain.PackSize{250, 5000},
+			orderSize: 4750,
+			policy:    "bounded-overshoot:5",
+			expected:  []domain.PackResult{{Size: 250, Count: 19}},
+		},
+		{
+			name:      "larger packs on ties",
+			packSizes: []domain.PackSize{1, 2, 3},
+			orderSize: 4,
+			policy:    "larger-packs",
+			expected:  []domain.PackResult{{Size: 3, Count: 1}, {Size: 1, Count: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+			mockRepo.EXPECT().GetPackSizes().Return(tt.packSizes)
+			mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+			useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+			result, err := useCase.Execute(context.Background(), tt.orderSize, domain.CalculateOptions{Policy: tt.policy})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalculatePacksUseCase_DefaultPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 5000}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithDefaultPolicy(FewestPacks{}))
+
+	result, err := useCase.Execute(context.Background(), 4750, domain.CalculateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 5000, Count: 1}}, result)
+
+	// A request can still pick another policy.
+	result, err = useCase.Execute(context.Background(), 4750, domain.CalculateOptions{Policy: "fewest-items"})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 250, Count: 19}}, result)
+}
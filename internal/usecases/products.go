@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"regexp"
+)
+
+// idPattern keeps product and warehouse IDs usable as URL path segments.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type ProductsUseCase struct {
+	repo   domain.ProductRepository
+	tables *PackTablesByID
+}
+
+// NewProductsUseCase evicts a product's tables whenever it is saved or
+// deleted.
+func NewProductsUseCase(repo domain.ProductRepository, tables *PackTablesByID) *ProductsUseCase {
+	return &ProductsUseCase{repo: repo, tables: tables}
+}
+
+// SaveProduct adds a product or replaces its pack sizes. The sizes follow the
+// same rules as UpdatePackSizes.
+func (uc *ProductsUseCase) SaveProduct(product domain.Product) error {
+	if !idPattern.MatchString(product.ID) {
+		return domain.ErrInvalidProductID
+	}
+
+	sizes, err := validatePackSizes(product.PackSizes)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.SaveProduct(domain.Product{ID: product.ID, PackSizes: sizes}); err != nil {
+		return err
+	}
+	uc.tables.evict(product.ID)
+	return nil
+}
+
+func (uc *ProductsUseCase) GetProducts() []domain.Product {
+	return uc.repo.GetProducts()
+}
+
+func (uc *ProductsUseCase) DeleteProduct(id string) error {
+	if err := uc.repo.DeleteProduct(id); err != nil {
+		return err
+	}
+	uc.tables.evict(id)
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProductsUseCase_SaveProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		product domain.Product
+		wantErr error
+		stored  *domain.Product
+	}{
+		{
+			name:    "sizes are deduplicated and sorted",
+			product: domain.Product{ID: "widget-1", PackSizes: []domain.PackSize{500, 250, 500}},
+			stored:  &domain.Product{ID: "widget-1", PackSizes: []domain.PackSize{250, 500}},
+		},
+		{
+			name:    "empty ID",
+			product: domain.Product{PackSizes: []domain.PackSize{250}},
+			wantErr: domain.ErrInvalidProductID,
+		},
+		{
+			name:    "ID with a slash",
+			product: domain.Product{ID: "a/b", PackSizes: []domain.PackSize{250}},
+			wantErr: domain.ErrInvalidProductID,
+		},
+		{
+			name:    "ID too long",
+			product: domain.Product{ID: strings.Repeat("a", 65), PackSizes: []domain.PackSize{250}},
+			wantErr: domain.ErrInvalidProductID,
+		},
+		{
+			name:    "no sizes",
+			product: domain.Product{ID: "widget"},
+			wantErr: domain.ErrEmptyPackSizes,
+		},
+		{
+			name:    "invalid size",
+			product: domain.Product{ID: "widget", PackSizes: []domain.PackSize{250, 0}},
+			wantErr: domain.ErrInvalidPackSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockProductRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().SaveProduct(*tt.stored).Return(nil)
+			}
+
+			err := NewProductsUseCase(mockRepo, NewPackTablesByID(10)).SaveProduct(tt.product)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProductsUseCase_EvictsTables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	mockRepo.EXPECT().SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250}}).Return(nil)
+	mockRepo.EXPECT().DeleteProduct("bolt").Return(nil)
+	mockRepo.EXPECT().DeleteProduct("nut").Return(domain.ErrProductNotFound)
+
+	tables := NewPackTablesByID(10)
+	widget, bolt, nut := tables.get("widget"), tables.get("bolt"), tables.get("nut")
+	uc := NewProductsUseCase(mockRepo, tables)
+
+	require.NoError(t, uc.SaveProduct(domain.Product{ID: "widget", PackSizes: []domain.PackSize{250}}))
+	require.NoError(t, uc.DeleteProduct("bolt"))
+	assert.ErrorIs(t, uc.DeleteProduct("nut"), domain.ErrProductNotFound)
+
+	assert.Equal(t, 1, tables.len())
+	assert.NotSame(t, widget, tables.get("widget"))
+	assert.NotSame(t, bolt, tables.get("bolt"))
+	assert.Same(t, nut, tables.get("nut"))
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"math/big"
+)
+
+// centScale is the Decimal units in a cent.
+const centScale = domain.DecimalScale / 100
+
+// QuoteUseCase prices the packings of CalculatePacksUseCase.
+type QuoteUseCase struct {
+	packs *CalculatePacksUseCase
+}
+
+// NewQuoteUseCase packs orders with packs and prices them with the pricing in
+// its repository.
+func NewQuoteUseCase(packs *CalculatePacksUseCase) *QuoteUseCase {
+	return &QuoteUseCase{packs: packs}
+}
+
+// Quote packs the order like Execute and prices the packs. Every size in the
+// packing needs a price.
+func (uc *QuoteUseCase) Quote(ctx context.Context, orderSize int64, opts domain.CalculateOptions) (*domain.Quote, error) {
+	packs, err := uc.packs.Execute(ctx, orderSize, opts)
+	if err != nil {
+		return nil, err
+	}
+	return priceQuote(orderSize, packs, uc.packs.repo.GetPricing())
+}
+
+// priceQuote prices each size of the packing and sums the lines. The amounts
+// are exact until each line's amount and discount are rounded half up to the
+// cent; the totals add the rounded values, so lines always sum to them.
+func priceQuote(orderSize int64, packs []domain.PackResult, pricing domain.Pricing) (*domain.Quote, error) {
+	bySize := make(map[domain.PackSize]domain.PackPrice, len(pricing.Prices))
+	for _, p := range pricing.Prices {
+		bySize[p.Size] = p
+	}
+
+	quote := &domain.Quote{OrderSize: orderSize, Currency: pricing.Currency, Lines: make([]domain.QuoteLine, 0, len(packs))}
+	subtotal, discount := new(big.Int), new(big.Int)
+	for _, p := range packs {
+		price, ok := bySize[p.Size]
+		if !ok {
+			return nil, domain.ErrMissingPackPrices
+		}
+		items := p.Count * int64(p.Size) // within the packing's total
+
+		// Decimal units, then cents.
+		amount := new(big.Int).Mul(big.NewInt(items), big.NewInt(int64(price.UnitPrice)))
+		amount.Add(amount, new(big.Int).Mul(big.NewInt(p.Count), big.NewInt(int64(price.Surcharge))))
+		amount = roundDiv(amount, centScale)
+		percent := tierPercent(price.DiscountTiers, p.Count)
+		off := roundDiv(new(big.Int).Mul(amount, big.NewInt(int64(percent))), 100*domain.DecimalScale)
+
+		line := domain.QuoteLine{
+			Size:            p.Size,
+			Count:           p.Count,
+			Items:           items,
+			UnitPrice:       price.UnitPrice,
+			Surcharge:       price.Surcharge,
+			DiscountPercent: percent,
+		}
+		var err error
+		if line.Amount, err = centsDecimal(amount); err != nil {
+			return nil, err
+		}
+		if line.Discount, err = centsDecimal(off); err != nil {
+			return nil, err
+		}
+		line.Total = line.Amount - line.Discount
+		quote.Lines = append(quote.Lines, line)
+
+		subtotal.Add(subtotal, amount)
+		discount.Add(discount, off)
+	}
+
+	var err error
+	if quote.Subtotal, err = centsDecimal(subtotal); err != nil {
+		return nil, err
+	}
+	if quote.Discount, err = centsDecimal(discount); err != nil {
+		return nil, err
+	}
+	quote.Total = quote.Subtotal - quote.Discount
+	return quote, nil
+}
+
+// tierPercent returns the discount of the largest tier count reaches, or 0.
+// tiers must be ascending by MinPacks.
+func tierPercent(tiers []domain.DiscountTier, count int64) domain.Decimal {
+	percent := domain.Decimal(0)
+	for _, tier := range tiers {
+		if count >= tier.MinPacks {
+			percent = tier.Percent
+		}
+	}
+	return percent
+}
+
+// roundDiv returns a/b rounded half up, for a >= 0 and b > 0.
+func roundDiv(a *big.Int, b int64) *big.Int {
+	q, r := new(big.Int).QuoRem(a, big.NewInt(b), new(big.Int))
+	if r.Mul(r, big.NewInt(2)).Cmp(big.NewInt(b)) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// centsDecimal converts cents to a Decimal, or fails with ErrQuoteTooLarge if
+// the Decimal cannot hold them.
+func centsDecimal(cents *big.Int) (domain.Decimal, error) {
+	v := new(big.Int).Mul(cents, big.NewInt(centScale))
+	if !v.IsInt64() {
+		return 0, domain.ErrQuoteTooLarge
+	}
+	return domain.Decimal(v.Int64()), nil
+}
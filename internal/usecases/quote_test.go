@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestQuoteUseCase_Quote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+	mockRepo.EXPECT().GetConstraints().Return(nil)
+	mockRepo.EXPECT().GetPricing().Return(domain.Pricing{Currency: "EUR", Prices: []domain.PackPrice{
+		{Size: 250, UnitPrice: 120, Surcharge: 5000},
+		{Size: 500, UnitPrice: 100, Surcharge: 7500, DiscountTiers: []domain.DiscountTier{{MinPacks: 2, Percent: 25_000}}},
+	}})
+
+	uc := NewQuoteUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()))
+	quote, err := uc.Quote(context.Background(), 1001, domain.CalculateOptions{})
+	require.NoError(t, err)
+
+	// 1000 items at 0.01 plus 2 × 0.75 is 11.50, less 2.5%; 250 items at
+	// 0.012 plus 0.50 is 3.50.
+	assert.Equal(t, &domain.Quote{
+		OrderSize: 1001,
+		Currency:  "EUR",
+		Lines: []domain.QuoteLine{
+			{Size: 500, Count: 2, Items: 1000, UnitPrice: 100, Surcharge: 7500, Amount: 115_000, DiscountPercent: 25_000, Discount: 2900, Total: 112_100},
+			{Size: 250, Count: 1, Items: 250, UnitPrice: 120, Surcharge: 5000, Amount: 35_000, Total: 35_000},
+		},
+		Subtotal: 150_000,
+		Discount: 2900,
+		Total:    147_100,
+	}, quote)
+}
+
+func TestPriceQuote(t *testing.T) {
+	pricing := domain.Pricing{Currency: "USD", Prices: []domain.PackPrice{
+		{Size: 3, UnitPrice: 3333, DiscountTiers: []domain.DiscountTier{
+			{MinPacks: 2, Percent: 100_000},
+			{MinPacks: 10, Percent: 333_333},
+		}},
+	}}
+
+	t.Run("amounts round half up to the cent", func(t *testing.T) {
+		// 3 items at 0.3333 is 0.9999.
+		quote, err := priceQuote(3, []domain.PackResult{{Size: 3, Count: 1}}, pricing)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Decimal(10_000), quote.Subtotal)
+		assert.Equal(t, domain.Decimal(10_000), quote.Total)
+	})
+
+	t.Run("largest tier reached applies", func(t *testing.T) {
+		// 30 items at 0.3333 is 9.999, so 10.00; 33.3333% off is 3.33.
+		quote, err := priceQuote(30, []domain.PackResult{{Size: 3, Count: 10}}, pricing)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Decimal(333_333), quote.Lines[0].DiscountPercent)
+		assert.Equal(t, domain.Decimal(33_300), quote.Discount)
+		assert.Equal(t, domain.Decimal(66_700), quote.Total)
+	})
+
+	t.Run("missing price", func(t *testing.T) {
+		_, err := priceQuote(5, []domain.PackResult{{Size: 5, Count: 1}}, pricing)
+		assert.ErrorIs(t, err, domain.ErrMissingPackPrices)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		expensive := domain.Pricing{Currency: "USD", Prices: []domain.PackPrice{{Size: 1_000_000, UnitPrice: maxPrice * domain.DecimalScale}}}
+		_, err := priceQuote(math.MaxInt64, []domain.PackResult{{Size: 1_000_000, Count: math.MaxInt64 / 1_000_000}}, expensive)
+		assert.ErrorIs(t, err, domain.ErrQuoteTooLarge)
+	})
+}
@@ -0,0 +1,312 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"math"
+	"slices"
+	"sort"
+)
+
+const (
+	// maxWorkloadOrderSize bounds the amounts the recommendation search covers.
+	maxWorkloadOrderSize = 100_000
+	maxWorkloadOrders    = 10_000
+	// maxOrderWeight keeps weighted totals within int64.
+	maxOrderWeight = 1_000_000
+	// maxCandidateSizes bounds the sizes tried at every step of the search.
+	maxCandidateSizes = 64
+	candidateGridSize = 24
+	maxSwapRounds     = 3
+)
+
+// RecommendPackSizesUseCase searches for pack sets that serve a workload
+// better than the current one.
+type RecommendPackSizesUseCase struct {
+	packs   *CalculatePacksUseCase
+	history domain.OrderHistoryRepository
+}
+
+// NewRecommendPackSizesUseCase reads the current pack set and its tables from
+// packs and shares its compute budget. history supplies the workload for
+// requests without one.
+func NewRecommendPackSizesUseCase(packs *CalculatePacksUseCase, history domain.OrderHistoryRepository) *RecommendPackSizesUseCase {
+	return &RecommendPackSizesUseCase{packs: packs, history: history}
+}
+
+// Recommend searches for at most req.Count pack sizes within the bounds and
+// scores them next to the current pack set. Pack sets rank like the default
+// rules rank packings: least overshoot over the workload, then fewest packs.
+// Without uploaded orders it uses the recorded history, skipping orders above
+// maxWorkloadOrderSize.
+func (uc *RecommendPackSizesUseCase) Recommend(ctx context.Context, req domain.RecommendationRequest) (*domain.Recommendation, error) {
+	if req.Count < 1 || req.Count > maxPackCount || req.MinSize < 1 || req.MinSize > req.MaxSize || req.MaxSize > maxPackSize {
+		return nil, domain.ErrInvalidRecommendation
+	}
+
+	orders := req.Orders
+	if len(orders) == 0 {
+		for _, order := range uc.history.GetOrders() {
+			if order.OrderSize <= maxWorkloadOrderSize {
+				orders = append(orders, order)
+			}
+		}
+	}
+	orders, err := aggregateWorkload(orders, maxWorkloadOrderSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	current := uc.packs.repo.GetPackSizes()
+	sizes, err := searchPackSizes(ctx, orders, int(req.MinSize), int(req.MaxSize), req.Count, current)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := newPackTable(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+	recommended, _, err := scorePackSet(ctx, table, orders)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.Recommendation{Recommended: recommended}
+
+	if len(current) > 0 {
+		table, err := uc.packs.tables.get(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+		score, _, err := scorePackSet(ctx, table, orders)
+		if err != nil {
+			return nil, err
+		}
+		result.Current = &score
+		result.OvershootSaved = score.Overshoot - recommended.Overshoot
+		result.PacksSaved = score.PackCount - recommended.PackCount
+	}
+	return result, nil
+}
+
+// aggregateWorkload checks a workload and returns it sorted by order size,
+// with the weights of repeated sizes added up.
+func aggregateWorkload(orders []domain.WeightedOrder, maxOrderSize int64) ([]domain.WeightedOrder, error) {
+	if len(orders) == 0 {
+		return nil, domain.ErrEmptyWorkload
+	}
+	if len(orders) > maxWorkloadOrders {
+		return nil, domain.ErrInvalidWorkload
+	}
+
+	weights := make(map[int64]int64, len(orders))
+	for _, order := range orders {
+		if order.OrderSize < 1 || order.OrderSize > maxOrderSize || order.Weight < 1 || order.Weight > maxOrderWeight {
+			return nil, domain.ErrInvalidWorkload
+		}
+		weights[order.OrderSize] += order.Weight
+	}
+
+	result := make([]domain.WeightedOrder, 0, len(weights))
+	for size, weight := range weights {
+		result = append(result, domain.WeightedOrder{OrderSize: size, Weight: weight})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].OrderSize < result[j].OrderSize })
+	return result, nil
+}
+
+// scorePackSet packs every order of the workload with calculateOptimalPacks
+// and returns the totals along with each order's packing.
+func scorePackSet(ctx context.Context, table *packTable, orders []domain.WeightedOrder) (domain.PackSetScore, []map[int]int64, error) {
+	score := domain.PackSetScore{PackSizes: make([]domain.PackSize, len(table.sizes))}
+	for i, size := range table.sizes {
+		score.PackSizes[i] = domain.PackSize(size)
+	}
+
+	packings := make([]map[int]int64, len(orders))
+	bySize := make(map[int]int64, len(table.sizes))
+	for i, order := range orders {
+		counts, err := calculateOptimalPacks(ctx, order.OrderSize, table)
+		if err != nil {
+			return domain.PackSetScore{}, nil, err
+		}
+		total := int64(0)
+		for size, n := range counts {
+			total += int64(size) * n
+			bySize[size] += order.Weight * n
+		}
+		score.TotalItems += order.Weight * total
+		score.Overshoot += order.Weight * (total - order.OrderSize)
+		score.PackCount += order.Weight * sumCounts(counts)
+		packings[i] = counts
+	}
+	score.Packs = toPackResults(bySize)
+	return score, packings, nil
+}
+
+// searchScore ranks pack sets for a workload: least overshoot, then fewest
+// packs.
+type searchScore struct {
+	overshoot, packs int64
+}
+
+func (a searchScore) less(b searchScore) bool {
+	return a.overshoot < b.overshoot || (a.overshoot == b.overshoot && a.packs < b.packs)
+}
+
+// searchPackSizes picks the sizes greedily: starting from none, it adds the
+// candidate that improves the set most, and stops early once none does. Then
+// it swaps single sizes for better candidates, which fixes choices that only
+// paid off before later sizes were added.
+//
+// Both phases keep the fewest packs per amount, up to the largest order plus
+// the largest candidate, for all sizes but the one being picked, so trying a
+// candidate is one pass of the unbounded DP over those amounts.
+func searchPackSizes(ctx context.Context, orders []domain.WeightedOrder, minSize, maxSize, count int, current []domain.PackSize) ([]int, error) {
+	candidates := candidateSizes(orders, minSize, maxSize, current)
+	width := int(orders[len(orders)-1].OrderSize) + candidates[len(candidates)-1] + 1
+	base := make([]int32, width)
+	trial := make([]int32, width)
+
+	// bestWith returns the candidate that scores best on top of base, if it
+	// beats the score to beat.
+	bestWith := func(chosen []int, toBeat *searchScore) (int, searchScore, error) {
+		pick, pickScore := 0, searchScore{}
+		for _, c := range candidates {
+			if slices.Contains(chosen, c) {
+				continue
+			}
+			if err := contextError(ctx); err != nil {
+				return 0, searchScore{}, err
+			}
+
+			copy(trial, base)
+			addPackSize(trial, c)
+			s := workloadScore(trial, orders)
+			if (pick == 0 || s.less(pickScore)) && (toBeat == nil || s.less(*toBeat)) {
+				pick, pickScore = c, s
+			}
+		}
+		return pick, pickScore, nil
+	}
+
+	var chosen []int
+	var best searchScore
+	resetPacks(base)
+	for len(chosen) < count {
+		toBeat := &best
+		if len(chosen) == 0 {
+			toBeat = nil
+		}
+		pick, score, err := bestWith(chosen, toBeat)
+		if err != nil {
+			return nil, err
+		}
+		if pick == 0 {
+			break
+		}
+		addPackSize(base, pick)
+		chosen = append(chosen, pick)
+		best = score
+	}
+
+	for round := 0; round < maxSwapRounds; round++ {
+		swapped := false
+		for i := range chosen {
+			resetPacks(base)
+			for j, size := range chosen {
+				if j != i {
+					addPackSize(base, size)
+				}
+			}
+			pick, score, err := bestWith(chosen, &best)
+			if err != nil {
+				return nil, err
+			}
+			if pick != 0 {
+				chosen[i], best, swapped = pick, score, true
+			}
+		}
+		if !swapped {
+			break
+		}
+	}
+
+	slices.Sort(chosen)
+	return chosen, nil
+}
+
+// resetPacks leaves only the empty packing.
+func resetPacks(packs []int32) {
+	packs[0] = 0
+	for x := 1; x < len(packs); x++ {
+		packs[x] = -1
+	}
+}
+
+// addPackSize updates the fewest packs per amount for one more size.
+func addPackSize(packs []int32, size int) {
+	for x := size; x < len(packs); x++ {
+		if p := packs[x-size]; p >= 0 && (packs[x] < 0 || p+1 < packs[x]) {
+			packs[x] = p + 1
+		}
+	}
+}
+
+// workloadScore ships every order as the smallest reachable amount at or above
+// it, sweeping the amounts down once. orders must be ascending; some amount
+// is always reachable within one candidate of every order.
+func workloadScore(packs []int32, orders []domain.WeightedOrder) searchScore {
+	var s searchScore
+	next := -1
+	i := len(orders) - 1
+	for x := len(packs) - 1; x > 0 && i >= 0; x-- {
+		if packs[x] >= 0 {
+			next = x
+		}
+		if orders[i].OrderSize == int64(x) {
+			w := orders[i].Weight
+			s.overshoot += w * int64(next-x)
+			s.packs += w * int64(packs[next])
+			i--
+		}
+	}
+	return s
+}
+
+// candidateSizes returns the sizes the search tries, ascending: the current
+// sizes, the most frequent order sizes and a geometric grid between the
+// bounds. Sizes above the largest order do no better than one equal to it, so
+// the range stops there.
+func candidateSizes(orders []domain.WeightedOrder, minSize, maxSize int, current []domain.PackSize) []int {
+	hi := min(maxSize, max(minSize, int(orders[len(orders)-1].OrderSize)))
+
+	seen := make(map[int]bool)
+	var candidates []int
+	add := func(size int) {
+		if size >= minSize && size <= hi && !seen[size] && len(candidates) < maxCandidateSizes {
+			seen[size] = true
+			candidates = append(candidates, size)
+		}
+	}
+
+	for _, size := range current {
+		add(int(size))
+	}
+	for i := 0; i < candidateGridSize; i++ {
+		ratio := float64(i) / (candidateGridSize - 1)
+		add(int(math.Round(float64(minSize) * math.Pow(float64(hi)/float64(minSize), ratio))))
+	}
+
+	byWeight := slices.Clone(orders)
+	sort.SliceStable(byWeight, func(i, j int) bool { return byWeight[i].Weight > byWeight[j].Weight })
+	for _, order := range byWeight {
+		add(int(order.OrderSize))
+	}
+
+	slices.Sort(candidates)
+	return candidates
+}
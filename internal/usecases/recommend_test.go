@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecommendPackSizesUseCase_Recommend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+
+	uc := NewRecommendPackSizesUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()), nil)
+	result, err := uc.Recommend(context.Background(), domain.RecommendationRequest{
+		Orders:  []domain.WeightedOrder{{OrderSize: 300, Weight: 5}, {OrderSize: 700, Weight: 2}, {OrderSize: 300, Weight: 1}},
+		Count:   2,
+		MinSize: 100,
+		MaxSize: 1000,
+	})
+	require.NoError(t, err)
+
+	// Greedily 100 comes first, as it ships both orders exactly; swapping
+	// it out saves the extra packs.
+	assert.Equal(t, &domain.Recommendation{
+		Recommended: domain.PackSetScore{
+			PackSizes:  []domain.PackSize{300, 700},
+			TotalItems: 3200,
+			Overshoot:  0,
+			PackCount:  8,
+			Packs:      []domain.PackResult{{Size: 700, Count: 2}, {Size: 300, Count: 6}},
+		},
+		Current: &domain.PackSetScore{
+			PackSizes:  []domain.PackSize{250, 500},
+			TotalItems: 6*500 + 2*750,
+			Overshoot:  6*200 + 2*50,
+			PackCount:  6*1 + 2*2,
+			Packs:      []domain.PackResult{{Size: 500, Count: 8}, {Size: 250, Count: 2}},
+		},
+		OvershootSaved: 1300,
+		PacksSaved:     2,
+	}, result)
+}
+
+func TestRecommendPackSizesUseCase_FromHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return(nil)
+	mockHistory := mocks.NewMockOrderHistoryRepository(ctrl)
+	mockHistory.EXPECT().GetOrders().Return([]domain.WeightedOrder{{OrderSize: 120, Weight: 3}, {OrderSize: 5_000_000, Weight: 1}})
+
+	uc := NewRecommendPackSizesUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()), mockHistory)
+	result, err := uc.Recommend(context.Background(), domain.RecommendationRequest{Count: 3, MinSize: 50, MaxSize: 500})
+	require.NoError(t, err)
+
+	// The order above the search range is skipped, and one size covers the
+	// rest, so the search stops early.
+	assert.Equal(t, &domain.Recommendation{
+		Recommended: domain.PackSetScore{PackSizes: []domain.PackSize{120}, TotalItems: 360, Overshoot: 0, PackCount: 3, Packs: []domain.PackResult{{Size: 120, Count: 3}}},
+	}, result)
+}
+
+func TestRecommendPackSizesUseCase_NeverWorseThanCurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{120, 250, 990})
+
+	uc := NewRecommendPackSizesUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()), nil)
+	result, err := uc.Recommend(context.Background(), domain.RecommendationRequest{
+		Orders:  []domain.WeightedOrder{{OrderSize: 137, Weight: 4}, {OrderSize: 480, Weight: 1}, {OrderSize: 1021, Weight: 3}, {OrderSize: 2500, Weight: 2}},
+		Count:   3,
+		MinSize: 100,
+		MaxSize: 1000,
+	})
+	require.NoError(t, err)
+
+	// The current sizes are candidates, so the search never does worse.
+	assert.GreaterOrEqual(t, result.OvershootSaved, int64(0))
+	assert.LessOrEqual(t, len(result.Recommended.PackSizes), 3)
+	for _, size := range result.Recommended.PackSizes {
+		assert.True(t, size >= 100 && size <= 1000, "size %d", size)
+	}
+}
+
+func TestRecommendPackSizesUseCase_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHistory := mocks.NewMockOrderHistoryRepository(ctrl)
+	mockHistory.EXPECT().GetOrders().Return(nil)
+	uc := NewRecommendPackSizesUseCase(NewCalculatePacksUseCase(nil, NewPackTableCache()), mockHistory)
+	orders := []domain.WeightedOrder{{OrderSize: 100, Weight: 1}}
+
+	tests := []struct {
+		name    string
+		req     domain.RecommendationRequest
+		wantErr error
+	}{
+		{name: "zero count", req: domain.RecommendationRequest{Orders: orders, Count: 0, MinSize: 1, MaxSize: 10}, wantErr: domain.ErrInvalidRecommendation},
+		{name: "too many sizes", req: domain.RecommendationRequest{Orders: orders, Count: 21, MinSize: 1, MaxSize: 10}, wantErr: domain.ErrInvalidRecommendation},
+		{name: "inverted bounds", req: domain.RecommendationRequest{Orders: orders, Count: 1, MinSize: 10, MaxSize: 1}, wantErr: domain.ErrInvalidRecommendation},
+		{name: "size above limit", req: domain.RecommendationRequest{Orders: orders, Count: 1, MinSize: 1, MaxSize: 1_000_001}, wantErr: domain.ErrInvalidRecommendation},
+		{name: "order too large", req: domain.RecommendationRequest{Orders: []domain.WeightedOrder{{OrderSize: 100_001, Weight: 1}}, Count: 1, MinSize: 1, MaxSize: 10}, wantErr: domain.ErrInvalidWorkload},
+		{name: "zero weight", req: domain.RecommendationRequest{Orders: []domain.WeightedOrder{{OrderSize: 10, Weight: 0}}, Count: 1, MinSize: 1, MaxSize: 10}, wantErr: domain.ErrInvalidWorkload},
+		{name: "no orders or history", req: domain.RecommendationRequest{Count: 1, MinSize: 1, MaxSize: 10}, wantErr: domain.ErrEmptyWorkload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Recommend(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
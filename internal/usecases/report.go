@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+)
+
+// maxReportOrderSize bounds the orders a report covers, and with them the
+// breakpoints it lists.
+const maxReportOrderSize = 100_000
+
+// PackSetReportUseCase reports how a pack set serves a range of orders.
+type PackSetReportUseCase struct {
+	packs *CalculatePacksUseCase
+}
+
+// NewPackSetReportUseCase reads the current pack set and its tables from
+// packs and shares its compute budget.
+func NewPackSetReportUseCase(packs *CalculatePacksUseCase) *PackSetReportUseCase {
+	return &PackSetReportUseCase{packs: packs}
+}
+
+// Report covers the orders 1..upTo under the FewestItems policy, for the
+// proposed sizes or, when sizes is empty, the current pack set. A proposed set
+// gets its own tables and leaves the shared ones alone. The overshoot of the
+// orders between breakpoints is derived rather than calculated.
+func (uc *PackSetReportUseCase) Report(ctx context.Context, sizes []domain.PackSize, upTo int64) (*domain.PackSetReport, error) {
+	if upTo < 1 || upTo > maxReportOrderSize {
+		return nil, domain.ErrInvalidReportRange
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	table, err := uc.reportTable(ctx, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.PackSetReport{
+		PackSizes:   make([]domain.PackSize, len(table.sizes)),
+		UpTo:        upTo,
+		UnusedSizes: []domain.PackSize{},
+		Breakpoints: []domain.Breakpoint{},
+	}
+	for i, size := range table.sizes {
+		report.PackSizes[i] = domain.PackSize(size)
+	}
+
+	used := make(map[int]bool, len(table.sizes))
+	overshoot := int64(0)
+	err = sweepBreakpoints(ctx, table, upTo, func(b domain.Breakpoint) {
+		for _, p := range b.Packs {
+			used[int(p.Size)] = true
+		}
+
+		// The orders b.OrderSize..last overshoot by TotalItems-b.OrderSize
+		// down to TotalItems-last.
+		total := b.TotalItems
+		last := min(total, upTo)
+		overshoot += (2*total - b.OrderSize - last) * (last - b.OrderSize + 1) / 2
+		if total-b.OrderSize > report.WorstOvershoot {
+			report.WorstOvershoot = total - b.OrderSize
+			report.WorstOrderSize = b.OrderSize
+		}
+		if total <= upTo {
+			report.ExactOrders++
+		}
+		report.Breakpoints = append(report.Breakpoints, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.AverageOvershoot = float64(overshoot) / float64(upTo)
+
+	for _, size := range table.sizes {
+		if !used[size] {
+			report.UnusedSizes = append(report.UnusedSizes, domain.PackSize(size))
+		}
+	}
+	return report, nil
+}
+
+// BreakpointTable lists the packing of every order from 1 to upTo under the
+// FewestItems policy for the current pack set, one interval per packing, as
+// a lookup table for people packing by hand.
+func (uc *PackSetReportUseCase) BreakpointTable(ctx context.Context, upTo int64) (*domain.BreakpointTable, error) {
+	if upTo < 1 || upTo > maxReportOrderSize {
+		return nil, domain.ErrInvalidReportRange
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	version := uc.packs.repo.PackSetVersion()
+	table, err := uc.reportTable(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BreakpointTable{
+		PackSizes:      make([]domain.PackSize, len(table.sizes)),
+		PackSetVersion: version,
+		UpTo:           upTo,
+		Intervals:      []domain.BreakpointInterval{},
+	}
+	for i, size := range table.sizes {
+		result.PackSizes[i] = domain.PackSize(size)
+	}
+	err = sweepBreakpoints(ctx, table, upTo, func(b domain.Breakpoint) {
+		result.Intervals = append(result.Intervals, domain.BreakpointInterval{
+			From:       b.OrderSize,
+			To:         min(b.TotalItems, upTo),
+			Packs:      b.Packs,
+			TotalItems: b.TotalItems,
+			PackCount:  b.PackCount,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sweepBreakpoints passes fn the breakpoints of the orders 1..upTo in order.
+//
+// Every order up to the total of the previous order's packing ships the same
+// packing, so one pass over the shippable totals calls calculateOptimalPacks
+// once per breakpoint. Each call costs about as much as a calculation, so
+// sets whose packings hold thousands of packs are left to the compute budget.
+func sweepBreakpoints(ctx context.Context, table *packTable, upTo int64, fn func(domain.Breakpoint)) error {
+	for orderSize := int64(1); orderSize <= upTo; {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		counts, err := calculateOptimalPacks(ctx, orderSize, table)
+		if err != nil {
+			return err
+		}
+		total, packs := int64(0), int64(0)
+		for size, n := range counts {
+			total += int64(size) * n
+			packs += n
+		}
+
+		fn(domain.Breakpoint{
+			OrderSize:  orderSize,
+			Packs:      toPackResults(counts),
+			TotalItems: total,
+			PackCount:  packs,
+		})
+		orderSize = total + 1
+	}
+	return nil
+}
+
+// reportTable returns the tables of the proposed sizes, or of the current pack
+// set when there are none.
+func (uc *PackSetReportUseCase) reportTable(ctx context.Context, sizes []domain.PackSize) (*packTable, error) {
+	if len(sizes) == 0 {
+		current := uc.packs.repo.GetPackSizes()
+		if len(current) == 0 {
+			return nil, domain.ErrNoPackSizes
+		}
+		return uc.packs.tables.get(ctx, current)
+	}
+
+	unique, err := validatePackSizes(sizes)
+	if err != nil {
+		return nil, err
+	}
+	return newPackTable(ctx, normalizePackSizes(unique))
+}
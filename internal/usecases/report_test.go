@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPackSetReportUseCase_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000})
+
+	uc := NewPackSetReportUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()))
+	report, err := uc.Report(context.Background(), nil, 1000)
+	require.NoError(t, err)
+
+	// Orders 1..250 overshoot by 249..0, and so on for every run of 250.
+	assert.Equal(t, &domain.PackSetReport{
+		PackSizes:        []domain.PackSize{250, 500, 1000},
+		UpTo:             1000,
+		ExactOrders:      4,
+		AverageOvershoot: 124.5,
+		WorstOvershoot:   249,
+		WorstOrderSize:   1,
+		UnusedSizes:      []domain.PackSize{},
+		Breakpoints: []domain.Breakpoint{
+			{OrderSize: 1, Packs: []domain.PackResult{{Size: 250, Count: 1}}, TotalItems: 250, PackCount: 1},
+			{OrderSize: 251, Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, PackCount: 1},
+			{OrderSize: 501, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, PackCount: 2},
+			{OrderSize: 751, Packs: []domain.PackResult{{Size: 1000, Count: 1}}, TotalItems: 1000, PackCount: 1},
+		},
+	}, report)
+}
+
+func TestPackSetReportUseCase_MatchesPerOrderCalls(t *testing.T) {
+	sizes := []domain.PackSize{23, 31, 53, 200}
+	const upTo = 3000
+
+	uc := NewPackSetReportUseCase(NewCalculatePacksUseCase(nil, NewPackTableCache()))
+	report, err := uc.Report(context.Background(), sizes, upTo)
+	require.NoError(t, err)
+
+	table := mustPackTable(t, 23, 31, 53, 200)
+	var sum, worst, exact int64
+	used := map[int]bool{}
+	var prev map[int]int64
+	breakpoints := 0
+	for orderSize := int64(1); orderSize <= upTo; orderSize++ {
+		counts := mustOptimalPacks(t, orderSize, table)
+		total, _ := sumPacks(counts)
+		sum += total - orderSize
+		worst = max(worst, total-orderSize)
+		if total == orderSize {
+			exact++
+		}
+		for size := range counts {
+			used[size] = true
+		}
+		if !assert.ObjectsAreEqual(prev, counts) {
+			breakpoints++
+		}
+		prev = counts
+	}
+
+	assert.Equal(t, exact, report.ExactOrders)
+	assert.Equal(t, worst, report.WorstOvershoot)
+	assert.InDelta(t, float64(sum)/upTo, report.AverageOvershoot, 1e-9)
+	assert.Len(t, report.Breakpoints, breakpoints)
+	assert.Len(t, used, len(sizes)-len(report.UnusedSizes))
+}
+
+func TestPackSetReportUseCase_ProposedSetKeepsSharedTables(t *testing.T) {
+	cache := NewPackTableCache()
+	uc := NewPackSetReportUseCase(NewCalculatePacksUseCase(nil, cache))
+
+	// Orders up to 100 never need the 5000 pack.
+	report, err := uc.Report(context.Background(), []domain.PackSize{5000, 250, 250}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PackSize{250, 5000}, report.PackSizes)
+	assert.Equal(t, []domain.PackSize{5000}, report.UnusedSizes)
+	assert.Nil(t, cache.current.Load())
+}
+
+func TestPackSetReportUseCase_BreakpointTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000})
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+
+	uc := NewPackSetReportUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()))
+	table, err := uc.BreakpointTable(context.Background(), 900)
+	require.NoError(t, err)
+
+	// The last interval stops at upTo but still ships 1000.
+	assert.Equal(t, &domain.BreakpointTable{
+		PackSizes:      []domain.PackSize{250, 500, 1000},
+		PackSetVersion: 1,
+		UpTo:           900,
+		Intervals: []domain.BreakpointInterval{
+			{From: 1, To: 250, Packs: []domain.PackResult{{Size: 250, Count: 1}}, TotalItems: 250, PackCount: 1},
+			{From: 251, To: 500, Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, PackCount: 1},
+			{From: 501, To: 750, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, PackCount: 2},
+			{From: 751, To: 900, Packs: []domain.PackResult{{Size: 1000, Count: 1}}, TotalItems: 1000, PackCount: 1},
+		},
+	}, table)
+}
+
+func TestPackSetReportUseCase_BreakpointTableMatchesExecute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{23, 31, 53, 200}).AnyTimes()
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1))
+	packs := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+
+	const upTo = 2000
+	table, err := NewPackSetReportUseCase(packs).BreakpointTable(context.Background(), upTo)
+	require.NoError(t, err)
+
+	// The intervals cover every order once, each with what Execute returns.
+	next := int64(1)
+	for _, interval := range table.Intervals {
+		require.Equal(t, next, interval.From)
+		for orderSize := interval.From; orderSize <= interval.To; orderSize++ {
+			result, err := packs.Execute(context.Background(), orderSize, domain.CalculateOptions{})
+			require.NoError(t, err)
+			require.Equal(t, interval.Packs, result, "order %d", orderSize)
+		}
+		next = interval.To + 1
+	}
+	assert.Equal(t, int64(upTo+1), next)
+}
+
+func TestPackSetReportUseCase_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return(nil)
+	uc := NewPackSetReportUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()))
+
+	_, err := uc.Report(context.Background(), nil, 100)
+	assert.ErrorIs(t, err, domain.ErrNoPackSizes)
+
+	_, err = uc.Report(context.Background(), nil, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidReportRange)
+
+	_, err = uc.Report(context.Background(), nil, maxReportOrderSize+1)
+	assert.ErrorIs(t, err, domain.ErrInvalidReportRange)
+
+	_, err = uc.Report(context.Background(), []domain.PackSize{250, -1}, 100)
+	assert.ErrorIs(t, err, domain.ErrInvalidPackSize)
+
+	_, err = uc.BreakpointTable(context.Background(), 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidReportRange)
+}
+
+func TestPackSetVersion_AgreesAfterUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// A stock update bumps the pack-set version but keeps the pack sizes, so
+	// the solver tables are not rebuilt.
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 1000}).AnyTimes()
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	gomock.InOrder(
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(3),
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(2)).Times(3),
+	)
+
+	packs := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	reports := NewPackSetReportUseCase(packs)
+	for _, want := range []uint64{1, 2} {
+		calculation, err := packs.Explain(context.Background(), 501, domain.CalculateOptions{})
+		require.NoError(t, err)
+		table, err := reports.BreakpointTable(context.Background(), 100)
+		require.NoError(t, err)
+
+		assert.Equal(t, want, calculation.PackSetVersion)
+		assert.Equal(t, want, packs.Stats().PackSetVersion)
+		assert.Equal(t, want, table.PackSetVersion)
+	}
+}
@@ -0,0 +1,130 @@
+package usecases
+
+import "context"
+
+// residueTable stores, for every residue class modulo a reference pack size,
+// the cheapest combination of the other pack sizes that falls into that
+// class. Any amount in the class that is at least as large as the stored
+// combination is reached by topping it up with reference packs, so the table
+// answers queries for arbitrarily large amounts in O(modulus) memory.
+type residueTable struct {
+	modulus int
+	packs   []int   // the non-reference sizes, ascending
+	weight  []int64 // cost of the cheapest combination, -1 if the class is unreachable
+	amount  []int64 // items in that combination; the smallest one on equal cost
+	last    []uint8 // index into packs of the last pack of the combination
+
+	// covered reports, per pack, whether the modulus and the packs before it
+	// already reached its amount when it was added. That makes it a sum of
+	// smaller packs only when the cost is the amount itself, so that amount
+	// holds the smallest combination of each class.
+	covered []bool
+}
+
+// newResidueTable computes the table with the round-robin algorithm of Böcker
+// and Lipták: packs are added one at a time, and every residue cycle a pack
+// induces is relaxed in a single pass starting from its cheapest class. This
+// runs in O(len(packs) * modulus) time and needs no priority queue.
+//
+// cost must be positive for every pack so that combinations never loop.
+func newResidueTable(ctx context.Context, modulus int, packs []int, cost func(pack int) int64) (*residueTable, error) {
+	t := &residueTable{
+		modulus: modulus,
+		packs:   packs,
+		weight:  make([]int64, modulus),
+		amount:  make([]int64, modulus),
+		last:    make([]uint8, modulus),
+		covered: make([]bool, len(packs)),
+	}
+	for r := range t.weight {
+		t.weight[r] = -1
+	}
+	t.weight[0] = 0
+
+	for i, pack := range packs {
+		// One pass is O(modulus); checking between passes is frequent enough.
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+		t.covered[i] = t.reachable(int64(pack))
+
+		step := pack % modulus
+		if step == 0 {
+			// Adding the pack stays in the same class at extra cost.
+			continue
+		}
+		edge := cost(pack)
+		cycles := gcd(step, modulus)
+		cycleLen := modulus / cycles
+
+		for start := 0; start < cycles; start++ {
+			cheapest := -1
+			for j, r := 0, start; j < cycleLen; j, r = j+1, (r+step)%modulus {
+				if t.weight[r] >= 0 && (cheapest < 0 || t.cheaper(t.weight[r], t.amount[r], cheapest)) {
+					cheapest = r
+				}
+			}
+			if cheapest < 0 {
+				continue
+			}
+
+			for j, r := 0, cheapest; j < cycleLen-1; j++ {
+				next := (r + step) % modulus
+				w, a := t.weight[r]+edge, t.amount[r]+int64(pack)
+				if t.weight[next] < 0 || t.cheaper(w, a, next) {
+					t.weight[next] = w
+					t.amount[next] = a
+					t.last[next] = uint8(i)
+				}
+				r = next
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// cheaper reports whether a combination with the given cost and amount beats
+// the one stored for class r.
+func (t *residueTable) cheaper(weight, amount int64, r int) bool {
+	return weight < t.weight[r] || (weight == t.weight[r] && amount < t.amount[r])
+}
+
+// reachable reports whether the class of amount has a combination that fits
+// into amount, i.e. whether the table alone can answer for it.
+func (t *residueTable) reachable(amount int64) bool {
+	r := amount % int64(t.modulus)
+	return t.weight[r] >= 0 && t.amount[r] <= amount
+}
+
+// maxAmount returns the largest stored combination. From this amount on, every
+// reachable class is answered by the table.
+func (t *residueTable) maxAmount() int64 {
+	m := int64(0)
+	for r, a := range t.amount {
+		if t.weight[r] >= 0 && a > m {
+			m = a
+		}
+	}
+	return m
+}
+
+// addCombination adds the packs of the combination stored for class r to
+// counts and returns how many packs it holds.
+func (t *residueTable) addCombination(r int, counts map[int]int64) int64 {
+	n := int64(0)
+	for t.amount[r] > 0 {
+		pack := t.packs[t.last[r]]
+		counts[pack]++
+		n++
+		r = ((r-pack)%t.modulus + t.modulus) % t.modulus
+	}
+	return n
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
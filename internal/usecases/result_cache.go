@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"container/list"
+	"slices"
+	"sync"
+)
+
+// resultKey is everything a packing from Execute depends on. The pack-set
+// version stands for the pack sizes and their settings, so entries of older
+// versions are simply never asked for again and age out.
+type resultKey struct {
+	version             uint64
+	orderSize           int64
+	policy              string
+	respectStock        bool
+	exactOnly           bool
+	maxOvershoot        int64 // -1 for no cap
+	maxOvershootPercent int   // -1 for no cap
+}
+
+func newResultKey(version uint64, orderSize int64, policy Policy, opts domain.CalculateOptions) resultKey {
+	key := resultKey{
+		version:             version,
+		orderSize:           orderSize,
+		policy:              policy.Name(),
+		respectStock:        opts.RespectStock,
+		exactOnly:           opts.ExactOnly,
+		maxOvershoot:        -1,
+		maxOvershootPercent: -1,
+	}
+	if opts.MaxOvershoot != nil {
+		key.maxOvershoot = *opts.MaxOvershoot
+	}
+	if opts.MaxOvershootPercent != nil {
+		key.maxOvershootPercent = *opts.MaxOvershootPercent
+	}
+	return key
+}
+
+type resultEntry struct {
+	key   resultKey
+	packs []domain.PackResult
+}
+
+// ResultCache keeps the packings of the most recently calculated orders, up to
+// a fixed number, evicting the least recently used. It is safe for concurrent
+// use.
+type ResultCache struct {
+	mu        sync.Mutex
+	capacity  int
+	entries   map[resultKey]*list.Element
+	order     *list.List // front is the most recently used
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// NewResultCache returns a cache of up to capacity packings, at least one.
+func NewResultCache(capacity int) *ResultCache {
+	capacity = max(capacity, 1)
+	return &ResultCache{
+		capacity: capacity,
+		entries:  make(map[resultKey]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns a copy of the packing stored under key, so callers may keep it.
+func (c *ResultCache) get(key resultKey) ([]domain.PackResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var entry *resultEntry
+	el, ok := c.entries[key]
+	if ok {
+		entry, ok = el.Value.(*resultEntry)
+	}
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return slices.Clone(entry.packs), true
+}
+
+func (c *ResultCache) put(key resultKey, packs []domain.PackResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		if entry, ok := oldest.Value.(*resultEntry); ok {
+			delete(c.entries, entry.key)
+		}
+		c.evictions++
+	}
+	c.entries[key] = c.order.PushFront(&resultEntry{key: key, packs: slices.Clone(packs)})
+}
+
+func (c *ResultCache) Stats() domain.ResultCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := domain.ResultCacheStats{
+		Enabled:   true,
+		Capacity:  c.capacity,
+		Size:      c.order.Len(),
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+	if lookups := c.hits + c.misses; lookups > 0 {
+		stats.HitRate = float64(c.hits) / float64(lookups)
+	}
+	return stats
+}
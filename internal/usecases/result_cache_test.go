@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewResultCache(2)
+	key := func(orderSize int64) resultKey {
+		return newResultKey(1, orderSize, FewestItems{}, domain.CalculateOptions{})
+	}
+	packs := []domain.PackResult{{Size: 250, Count: 1}}
+
+	cache.put(key(1), packs)
+	cache.put(key(2), packs)
+	_, ok := cache.get(key(1))
+	require.True(t, ok)
+	cache.put(key(3), packs)
+
+	_, ok = cache.get(key(2))
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = cache.get(key(1))
+	assert.True(t, ok)
+	_, ok = cache.get(key(3))
+	assert.True(t, ok)
+
+	assert.Equal(t, domain.ResultCacheStats{
+		Enabled:   true,
+		Capacity:  2,
+		Size:      2,
+		Hits:      3,
+		Misses:    1,
+		Evictions: 1,
+		HitRate:   0.75,
+	}, cache.Stats())
+}
+
+func TestResultCache_KeysOnOptions(t *testing.T) {
+	zero, ten := int64(0), 10
+	base := newResultKey(1, 251, FewestItems{}, domain.CalculateOptions{})
+	for _, other := range []resultKey{
+		newResultKey(2, 251, FewestItems{}, domain.CalculateOptions{}),
+		newResultKey(1, 252, FewestItems{}, domain.CalculateOptions{}),
+		newResultKey(1, 251, FewestPacks{}, domain.CalculateOptions{}),
+		newResultKey(1, 251, FewestItems{}, domain.CalculateOptions{RespectStock: true}),
+		newResultKey(1, 251, FewestItems{}, domain.CalculateOptions{ExactOnly: true}),
+		newResultKey(1, 251, FewestItems{}, domain.CalculateOptions{MaxOvershoot: &zero}),
+		newResultKey(1, 251, FewestItems{}, domain.CalculateOptions{MaxOvershootPercent: &ten}),
+	} {
+		assert.NotEqual(t, base, other)
+	}
+}
+
+func TestResultCache_ReturnsCopies(t *testing.T) {
+	cache := NewResultCache(1)
+	key := newResultKey(1, 1, FewestItems{}, domain.CalculateOptions{})
+	packs := []domain.PackResult{{Size: 250, Count: 1}}
+
+	cache.put(key, packs)
+	packs[0].Count = 2
+	got, _ := cache.get(key)
+	got[0].Count = 3
+
+	got, _ = cache.get(key)
+	assert.Equal(t, []domain.PackResult{{Size: 250, Count: 1}}, got)
+}
+
+func TestCalculatePacksUseCase_ResultCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The second call for 501 is served without reading the pack set; a new
+	// version misses and calculates again.
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(2),
+		mockRepo.EXPECT().PackSetVersion().Return(uint64(2)).Times(2),
+	)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500}),
+		mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{300, 500}),
+	)
+	mockRepo.EXPECT().GetConstraints().Return(nil).Times(2)
+	mockHistory := mocks.NewMockOrderHistoryRepository(ctrl)
+	mockHistory.EXPECT().RecordOrder(int64(501)).Times(3)
+
+	cache := NewResultCache(10)
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithResultCache(cache), WithOrderHistory(mockHistory))
+
+	for range 2 {
+		packs, err := useCase.Execute(context.Background(), 501, domain.CalculateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, packs)
+	}
+
+	packs, err := useCase.Execute(context.Background(), 501, domain.CalculateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PackResult{{Size: 300, Count: 2}}, packs)
+
+	stats := useCase.Stats().ResultCache
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 2, stats.Size)
+}
+
+func TestCalculatePacksUseCase_ResultCache_SkipsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(3)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250}).Times(2)
+	mockRepo.EXPECT().GetConstraints().Return(nil).Times(2)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithResultCache(NewResultCache(10)))
+	for range 2 {
+		_, err := useCase.Execute(context.Background(), 251, domain.CalculateOptions{ExactOnly: true})
+		assert.Error(t, err)
+	}
+
+	// Invalid orders never reach the cache.
+	_, err := useCase.Execute(context.Background(), 0, domain.CalculateOptions{})
+	assert.ErrorIs(t, err, domain.ErrOrderSizePositive)
+
+	assert.Equal(t, domain.ResultCacheStats{Enabled: true, Capacity: 10, Misses: 2}, useCase.Stats().ResultCache)
+}
+
+func TestCalculatePacksUseCase_Stats_WithoutCache(t *testing.T) {
+	useCase := NewCalculatePacksUseCase(nil, NewPackTableCache())
+	assert.Equal(t, domain.CalculationStats{}, useCase.Stats())
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// shadowSolver re-checks a sample of live packings against the independent
+// search of VerifyPacking. Checks run in the background, so they never delay
+// or change a response; disagreements are logged and counted. At most one
+// check per CPU runs at a time, and samples beyond that are skipped.
+type shadowSolver struct {
+	percent float64
+	sample  func() float64 // uniform in [0, 100)
+	slots   chan struct{}
+
+	// mu orders starting checks against close, so none starts once close
+	// waits on wg.
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+
+	checked       atomic.Uint64
+	disagreements atomic.Uint64
+	inconclusive  atomic.Uint64
+	skipped       atomic.Uint64
+}
+
+func newShadowSolver(percent float64) *shadowSolver {
+	return &shadowSolver{
+		percent: percent,
+		sample:  func() float64 { return rand.Float64() * 100 },
+		slots:   make(chan struct{}, runtime.GOMAXPROCS(0)),
+	}
+}
+
+// observe checks the packing for a sampled share of calls. sizes must not
+// change afterwards.
+func (s *shadowSolver) observe(ctx context.Context, orderSize int64, sizes []int, packs []domain.PackResult) {
+	if s.sample() >= s.percent {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		s.skipped.Add(1)
+		return
+	}
+
+	// The check outlives the request, so it keeps only the values of ctx and
+	// a copy of the packs the caller may go on to change.
+	ctx = context.WithoutCancel(ctx)
+	packs = slices.Clone(packs)
+	s.wg.Go(func() {
+		defer func() { <-s.slots }()
+		s.check(ctx, orderSize, sizes, packs)
+	})
+}
+
+// close stops new checks and waits for those running to finish.
+func (s *shadowSolver) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *shadowSolver) check(ctx context.Context, orderSize int64, sizes []int, packs []domain.PackResult) {
+	err := verifyPacking(ctx, orderSize, sizes, packs)
+	s.checked.Add(1)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidPacking), errors.Is(err, domain.ErrPackingNotOptimal):
+		s.disagreements.Add(1)
+		slog.Error("shadow solver disagrees with packing",
+			"orderSize", orderSize,
+			"packSizes", sizes,
+			"packs", packs,
+			"error", err,
+		)
+	default:
+		s.inconclusive.Add(1)
+	}
+}
+
+func (s *shadowSolver) stats() domain.ShadowStats {
+	return domain.ShadowStats{
+		Enabled:       true,
+		Percent:       s.percent,
+		Checked:       s.checked.Load(),
+		Disagreements: s.disagreements.Load(),
+		Inconclusive:  s.inconclusive.Load(),
+		Skipped:       s.skipped.Load(),
+	}
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestShadowSolver_Check(t *testing.T) {
+	s := newShadowSolver(100)
+	sizes := []int{250, 500, 1000, 2000, 5000}
+
+	s.check(context.Background(), 501, sizes, []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}})
+	s.check(context.Background(), 501, sizes, []domain.PackResult{{Size: 250, Count: 3}})
+	s.check(context.Background(), 501, sizes, []domain.PackResult{{Size: 500, Count: 1}})
+	s.check(context.Background(), hardOrder, []int{999_961, 999_979, 999_983}, hardPacks)
+
+	assert.Equal(t, domain.ShadowStats{Enabled: true, Percent: 100, Checked: 4, Disagreements: 2, Inconclusive: 1}, s.stats())
+}
+
+func TestShadowSolver_Sampling(t *testing.T) {
+	s := newShadowSolver(10)
+	packs := []domain.PackResult{{Size: 250, Count: 1}}
+
+	s.sample = func() float64 { return 10 }
+	s.observe(context.Background(), 250, []int{250}, packs)
+	s.wg.Wait()
+	assert.Equal(t, uint64(0), s.stats().Checked)
+
+	s.sample = func() float64 { return 9.99 }
+	s.observe(context.Background(), 250, []int{250}, packs)
+	s.wg.Wait()
+	assert.Equal(t, uint64(1), s.stats().Checked)
+}
+
+func TestShadowSolver_SkipsWhenBusy(t *testing.T) {
+	s := newShadowSolver(100)
+	for range cap(s.slots) {
+		s.slots <- struct{}{}
+	}
+
+	s.observe(context.Background(), 250, []int{250}, []domain.PackResult{{Size: 250, Count: 1}})
+	s.wg.Wait()
+	assert.Equal(t, domain.ShadowStats{Enabled: true, Percent: 100, Skipped: 1}, s.stats())
+}
+
+func TestShadowSolver_Close(t *testing.T) {
+	s := newShadowSolver(100)
+	packs := []domain.PackResult{{Size: 250, Count: 1}}
+
+	s.observe(context.Background(), 250, []int{250}, packs)
+	s.close()
+	assert.Equal(t, uint64(1), s.stats().Checked)
+
+	s.observe(context.Background(), 250, []int{250}, packs)
+	s.close()
+	assert.Equal(t, domain.ShadowStats{Enabled: true, Percent: 100, Checked: 1}, s.stats())
+}
+
+func TestCalculatePacksUseCase_ShadowSolver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{23, 31, 53}).AnyTimes()
+	mockRepo.EXPECT().GetConstraints().Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetStock().Return(nil).AnyTimes()
+	mockRepo.EXPECT().PackSetVersion().Return(uint64(1)).Times(2)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache(), WithShadowSolver(100))
+	execute := func(orderSize int64, opts domain.CalculateOptions) {
+		t.Helper()
+		_, err := useCase.Execute(context.Background(), orderSize, opts)
+		require.NoError(t, err)
+		// One check at a time, so none is skipped for lack of a slot.
+		useCase.shadow.wg.Wait()
+	}
+	for _, orderSize := range []int64{1, 263, 500_000} {
+		execute(orderSize, domain.CalculateOptions{})
+	}
+	execute(500_000, domain.CalculateOptions{Policy: "larger-packs"})
+
+	// Neither other rankings nor stock-limited packings are checked.
+	execute(500_000, domain.CalculateOptions{Policy: "fewest-packs"})
+	execute(500_000, domain.CalculateOptions{RespectStock: true})
+
+	assert.Equal(t, domain.ShadowStats{Enabled: true, Percent: 100, Checked: 4}, useCase.Stats().Shadow)
+
+	// Once closed, packings are no longer checked.
+	useCase.Close()
+	execute(263, domain.CalculateOptions{})
+	assert.Equal(t, uint64(4), useCase.Stats().Shadow.Checked)
+}
@@ -0,0 +1,341 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+)
+
+const (
+	// exactParcelPacks is the most packs planParcels searches for the fewest
+	// parcels; larger packings keep the first-fit decreasing plan.
+	exactParcelPacks = 64
+	// parcelSearchBudget caps the placements that search tries.
+	parcelSearchBudget = 1 << 16
+)
+
+// PlanShipments packs the order like Execute and splits the packs into
+// parcels within the limits. Every size in the packing needs dimensions. The
+// plan reports whether its parcel count is proven the fewest.
+func (uc *CalculatePacksUseCase) PlanShipments(
+	ctx context.Context,
+	orderSize int64,
+	opts domain.CalculateOptions,
+	limits domain.ParcelLimits,
+) (*domain.ShipmentPlan, error) {
+	if limits.MaxWeight < 0 || limits.MaxVolume < 0 || limits.MaxWeight == 0 && limits.MaxVolume == 0 {
+		return nil, domain.ErrInvalidParcelLimits
+	}
+
+	packs, err := uc.Execute(ctx, orderSize, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	parcels, optimal, err := planParcels(packs, uc.repo.GetDimensions(), limits)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &domain.ShipmentPlan{Packs: packs, Parcels: parcels, Optimal: optimal}
+	for _, p := range parcels {
+		plan.ParcelCount += p.Count
+	}
+	return plan, nil
+}
+
+// parcelLoad is the items of a kind of pack to ship: its count and the
+// weight and volume of one pack.
+type parcelLoad struct {
+	size           domain.PackSize
+	count          int64
+	weight, volume int64
+}
+
+// parcelGroup is n parcels with the same contents.
+type parcelGroup struct {
+	n              int64
+	counts         map[int]int64
+	weight, volume int64
+}
+
+// fit returns how many more packs of the load one parcel of g takes. An
+// unlimited dimension still stops at the int64 range, so loads never
+// overflow.
+func (g parcelGroup) fit(load parcelLoad, limits domain.ParcelLimits) int64 {
+	capacity := func(limit int64) int64 {
+		if limit == 0 {
+			return math.MaxInt64
+		}
+		return limit
+	}
+	return min((capacity(limits.MaxWeight)-g.weight)/load.weight, (capacity(limits.MaxVolume)-g.volume)/load.volume)
+}
+
+// with returns n parcels of g with k more packs of the load each.
+func (g parcelGroup) with(n int64, load parcelLoad, k int64) parcelGroup {
+	counts := make(map[int]int64, len(g.counts)+1)
+	for size, c := range g.counts {
+		counts[size] = c
+	}
+	if k > 0 {
+		counts[int(load.size)] += k
+	}
+	return parcelGroup{n: n, counts: counts, weight: g.weight + k*load.weight, volume: g.volume + k*load.volume}
+}
+
+// planParcels groups the packs into parcels. First-fit decreasing gives the
+// first plan: sizes in order of the largest share of a parcel they take, each
+// filling the earliest parcels with room before opening new ones. When that
+// plan uses more parcels than the total weight or volume needs, packings of
+// up to exactParcelPacks packs are searched for the fewest. optimal reports
+// whether the parcel count is proven the fewest; larger packings, and
+// searches that run out of budget, keep the heuristic plan, which with one
+// limit set needs at most 11/9 of the fewest parcels, plus one.
+func planParcels(packs []domain.PackResult, dimensions []domain.PackDimensions, limits domain.ParcelLimits) (parcels []domain.Parcel, optimal bool, err error) {
+	bySize := make(map[domain.PackSize]domain.PackDimensions, len(dimensions))
+	for _, d := range dimensions {
+		bySize[d.Size] = d
+	}
+
+	loads := make([]parcelLoad, 0, len(packs))
+	for _, p := range packs {
+		d, ok := bySize[p.Size]
+		if !ok {
+			return nil, false, domain.ErrMissingPackDimensions
+		}
+		load := parcelLoad{size: p.Size, count: p.Count, weight: d.Weight, volume: d.Volume()}
+		if (parcelGroup{}).fit(load, limits) == 0 {
+			return nil, false, fmt.Errorf("%w: a pack of %d weighs %d g and takes %d cm³", domain.ErrPackExceedsParcel, p.Size, load.weight, load.volume)
+		}
+		loads = append(loads, load)
+	}
+	share := func(l parcelLoad) float64 {
+		s := 0.0
+		if limits.MaxWeight > 0 {
+			s = float64(l.weight) / float64(limits.MaxWeight)
+		}
+		if limits.MaxVolume > 0 {
+			s = max(s, float64(l.volume)/float64(limits.MaxVolume))
+		}
+		return s
+	}
+	sort.SliceStable(loads, func(i, j int) bool { return share(loads[i]) > share(loads[j]) })
+
+	groups := firstFitDecreasing(loads, limits)
+	var used int64
+	for _, g := range groups {
+		used += g.n
+	}
+	fewer, optimal := fewestParcels(loads, limits, used)
+	if fewer != nil {
+		groups = fewer
+	}
+
+	parcels = make([]domain.Parcel, len(groups))
+	for i, g := range groups {
+		parcels[i] = domain.Parcel{Count: g.n, Packs: toPackResults(g.counts), Weight: g.weight, Volume: g.volume}
+	}
+	return parcels, optimal, nil
+}
+
+// firstFitDecreasing fills parcels with the loads in order. Packs of one size
+// are alike, so parcels that end up alike are kept as one group and the plan
+// costs O(sizes²) however many packs there are.
+func firstFitDecreasing(loads []parcelLoad, limits domain.ParcelLimits) []parcelGroup {
+	var groups []parcelGroup
+	for _, load := range loads {
+		left := load.count
+		for i := 0; i < len(groups) && left > 0; i++ {
+			g := groups[i]
+			k := g.fit(load, limits)
+			if k == 0 {
+				continue
+			}
+			if k <= left/g.n {
+				groups[i] = g.with(g.n, load, k)
+				left -= k * g.n
+				continue
+			}
+
+			// The packs left fill fewer than all parcels of the group: the
+			// first ones take k each, the next one the rest, and the others
+			// stay as they are.
+			full, rest := left/k, left%k
+			untouched := g.n - full
+			var split []parcelGroup
+			if full > 0 {
+				split = append(split, g.with(full, load, k))
+			}
+			if rest > 0 {
+				split = append(split, g.with(1, load, rest))
+				untouched--
+			}
+			if untouched > 0 {
+				split = append(split, g.with(untouched, load, 0))
+			}
+			groups = slices.Replace(groups, i, i+1, split...)
+			left = 0
+		}
+
+		if left > 0 {
+			empty := parcelGroup{}
+			k := empty.fit(load, limits)
+			if full := left / k; full > 0 {
+				groups = append(groups, empty.with(full, load, k))
+			}
+			if rest := left % k; rest > 0 {
+				groups = append(groups, empty.with(1, load, rest))
+			}
+		}
+	}
+	return groups
+}
+
+// fewestParcels looks for a plan with fewer than used parcels, trying each
+// count up from the fewest the total weight and volume allow. It returns that
+// plan, or nil when none has fewer, and whether used or the plan returned is
+// proven the fewest.
+func fewestParcels(loads []parcelLoad, limits domain.ParcelLimits, used int64) ([]parcelGroup, bool) {
+	var packs, weight, volume int64
+	ok := true
+	for _, load := range loads {
+		packs += load.count
+		w, wok := checkedMul(load.count, load.weight)
+		v, vok := checkedMul(load.count, load.volume)
+		weight, wok = checkedAdd(weight, w)
+		volume, vok = checkedAdd(volume, v)
+		ok = ok && wok && vok
+	}
+	lower := int64(1)
+	if limits.MaxWeight > 0 {
+		lower = max(lower, ceilDiv(weight, limits.MaxWeight))
+	}
+	if limits.MaxVolume > 0 {
+		lower = max(lower, ceilDiv(volume, limits.MaxVolume))
+	}
+	if ok && lower >= used {
+		return nil, true
+	}
+	if !ok || packs > exactParcelPacks {
+		return nil, false
+	}
+
+	s := newParcelSearch(loads, limits)
+	for n := lower; n < used; n++ {
+		if s.fits(int(n)) {
+			return s.groups(), true
+		}
+		if s.nodes > parcelSearchBudget {
+			return nil, false
+		}
+	}
+	return nil, true
+}
+
+// parcelSearch places packs one at a time into a fixed number of parcels,
+// backtracking when one fits nowhere.
+type parcelSearch struct {
+	items                []parcelLoad // one pack each
+	maxWeight, maxVolume int64
+	weight, volume       []int64 // per parcel
+	parcel               []int   // per item
+	nodes                int
+}
+
+func newParcelSearch(loads []parcelLoad, limits domain.ParcelLimits) *parcelSearch {
+	s := &parcelSearch{maxWeight: limits.MaxWeight, maxVolume: limits.MaxVolume}
+	if s.maxWeight == 0 {
+		s.maxWeight = math.MaxInt64
+	}
+	if s.maxVolume == 0 {
+		s.maxVolume = math.MaxInt64
+	}
+	for _, load := range loads {
+		for range load.count {
+			s.items = append(s.items, parcelLoad{size: load.size, count: 1, weight: load.weight, volume: load.volume})
+		}
+	}
+	s.parcel = make([]int, len(s.items))
+	return s
+}
+
+// fits reports whether the packs fit in n parcels. The nodes it visits count
+// towards parcelSearchBudget.
+func (s *parcelSearch) fits(n int) bool {
+	s.weight, s.volume = make([]int64, n), make([]int64, n)
+	return s.place(0)
+}
+
+func (s *parcelSearch) place(i int) bool {
+	if i == len(s.items) {
+		return true
+	}
+	s.nodes++
+	if s.nodes > parcelSearchBudget {
+		return false
+	}
+
+	item := s.items[i]
+	for p := range s.weight {
+		if item.weight > s.maxWeight-s.weight[p] || item.volume > s.maxVolume-s.volume[p] {
+			continue
+		}
+		// Parcels holding the same weight and volume take the same packs from
+		// here on, so only the first of them is tried.
+		same := false
+		for q := range p {
+			if s.weight[q] == s.weight[p] && s.volume[q] == s.volume[p] {
+				same = true
+				break
+			}
+		}
+		if same {
+			continue
+		}
+
+		s.parcel[i] = p
+		s.weight[p] += item.weight
+		s.volume[p] += item.volume
+		if s.place(i + 1) {
+			return true
+		}
+		s.weight[p] -= item.weight
+		s.volume[p] -= item.volume
+	}
+	return false
+}
+
+// groups returns the parcels of the last placement that fit, with alike
+// parcels kept as one group.
+func (s *parcelSearch) groups() []parcelGroup {
+	parcels := make([]parcelGroup, len(s.weight))
+	for i, item := range s.items {
+		p := &parcels[s.parcel[i]]
+		if p.counts == nil {
+			p.counts = make(map[int]int64)
+		}
+		p.counts[int(item.size)]++
+		p.weight += item.weight
+		p.volume += item.volume
+	}
+
+	var groups []parcelGroup
+	for _, p := range parcels {
+		if len(p.counts) == 0 {
+			continue
+		}
+		i := slices.IndexFunc(groups, func(g parcelGroup) bool { return maps.Equal(g.counts, p.counts) })
+		if i < 0 {
+			p.n = 1
+			groups = append(groups, p)
+			continue
+		}
+		groups[i].n++
+	}
+	return groups
+}
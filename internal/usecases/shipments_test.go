@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testDimensions = []domain.PackDimensions{
+	{Size: 250, Weight: 250, Length: 10, Width: 10, Height: 10},
+	{Size: 500, Weight: 500, Length: 20, Width: 10, Height: 10},
+	{Size: 5000, Weight: 5000, Length: 30, Width: 20, Height: 20},
+}
+
+func TestCalculatePacksUseCase_PlanShipments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500, 5000})
+	mockRepo.EXPECT().GetConstraints().Return(nil)
+	mockRepo.EXPECT().GetDimensions().Return(testDimensions)
+
+	useCase := NewCalculatePacksUseCase(mockRepo, NewPackTableCache())
+	plan, err := useCase.PlanShipments(context.Background(), 10_600, domain.CalculateOptions{}, domain.ParcelLimits{MaxWeight: 8000})
+	require.NoError(t, err)
+
+	// The 5000s take a parcel each; the 500 and 250 fit next to the first.
+	assert.Equal(t, &domain.ShipmentPlan{
+		Packs:       []domain.PackResult{{Size: 5000, Count: 2}, {Size: 500, Count: 1}, {Size: 250, Count: 1}},
+		ParcelCount: 2,
+		Optimal:     true,
+		Parcels: []domain.Parcel{
+			{Count: 1, Packs: []domain.PackResult{{Size: 5000, Count: 1}, {Size: 500, Count: 1}, {Size: 250, Count: 1}}, Weight: 5750, Volume: 15000},
+			{Count: 1, Packs: []domain.PackResult{{Size: 5000, Count: 1}}, Weight: 5000, Volume: 12000},
+		},
+	}, plan)
+}
+
+func TestCalculatePacksUseCase_PlanShipments_InvalidLimits(t *testing.T) {
+	useCase := NewCalculatePacksUseCase(nil, NewPackTableCache())
+
+	for _, limits := range []domain.ParcelLimits{{}, {MaxWeight: -1, MaxVolume: 10}, {MaxWeight: 10, MaxVolume: -1}} {
+		_, err := useCase.PlanShipments(context.Background(), 100, domain.CalculateOptions{}, limits)
+		assert.ErrorIs(t, err, domain.ErrInvalidParcelLimits, "limits %+v", limits)
+	}
+}
+
+func TestPlanParcels(t *testing.T) {
+	tests := []struct {
+		name       string
+		dimensions []domain.PackDimensions
+		packs      []domain.PackResult
+		limits     domain.ParcelLimits
+		expected   []domain.Parcel
+		optimal    bool
+	}{
+		{
+			name:   "volume limit",
+			packs:  []domain.PackResult{{Size: 500, Count: 3}, {Size: 250, Count: 5}},
+			limits: domain.ParcelLimits{MaxVolume: 5000},
+			expected: []domain.Parcel{
+				{Count: 1, Packs: []domain.PackResult{{Size: 500, Count: 2}, {Size: 250, Count: 1}}, Weight: 1250, Volume: 5000},
+				{Count: 1, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 3}}, Weight: 1250, Volume: 5000},
+				{Count: 1, Packs: []domain.PackResult{{Size: 250, Count: 1}}, Weight: 250, Volume: 1000},
+			},
+			optimal: true,
+		},
+		{
+			name:   "alike parcels are grouped",
+			packs:  []domain.PackResult{{Size: 250, Count: 1_000_000_000_001}},
+			limits: domain.ParcelLimits{MaxWeight: 1000, MaxVolume: 1_000_000},
+			expected: []domain.Parcel{
+				{Count: 250_000_000_000, Packs: []domain.PackResult{{Size: 250, Count: 4}}, Weight: 1000, Volume: 4000},
+				{Count: 1, Packs: []domain.PackResult{{Size: 250, Count: 1}}, Weight: 250, Volume: 1000},
+			},
+			optimal: true,
+		},
+		{
+			name:   "a group split by a smaller size",
+			packs:  []domain.PackResult{{Size: 5000, Count: 3}, {Size: 250, Count: 7}},
+			limits: domain.ParcelLimits{MaxWeight: 6000},
+			expected: []domain.Parcel{
+				{Count: 1, Packs: []domain.PackResult{{Size: 5000, Count: 1}, {Size: 250, Count: 4}}, Weight: 6000, Volume: 16000},
+				{Count: 1, Packs: []domain.PackResult{{Size: 5000, Count: 1}, {Size: 250, Count: 3}}, Weight: 5750, Volume: 15000},
+				{Count: 1, Packs: []domain.PackResult{{Size: 5000, Count: 1}}, Weight: 5000, Volume: 12000},
+			},
+			optimal: true,
+		},
+		{
+			// First-fit decreasing puts 5 and 4 together, then 3 three times,
+			// and needs a third parcel for the 2.
+			name: "fewer parcels than first-fit decreasing",
+			dimensions: []domain.PackDimensions{
+				{Size: 5, Weight: 5, Length: 1, Width: 1, Height: 1},
+				{Size: 4, Weight: 4, Length: 1, Width: 1, Height: 1},
+				{Size: 3, Weight: 3, Length: 1, Width: 1, Height: 1},
+				{Size: 2, Weight: 2, Length: 1, Width: 1, Height: 1},
+			},
+			packs:  []domain.PackResult{{Size: 5, Count: 1}, {Size: 4, Count: 1}, {Size: 3, Count: 3}, {Size: 2, Count: 1}},
+			limits: domain.ParcelLimits{MaxWeight: 10},
+			expected: []domain.Parcel{
+				{Count: 1, Packs: []domain.PackResult{{Size: 5, Count: 1}, {Size: 3, Count: 1}, {Size: 2, Count: 1}}, Weight: 10, Volume: 3},
+				{Count: 1, Packs: []domain.PackResult{{Size: 4, Count: 1}, {Size: 3, Count: 2}}, Weight: 10, Volume: 3},
+			},
+			optimal: true,
+		},
+		{
+			// Too many packs to search, so the heuristic plan stands at 42
+			// parcels where 40 could do.
+			name: "large packings keep first-fit decreasing",
+			dimensions: []domain.PackDimensions{
+				{Size: 5, Weight: 5, Length: 1, Width: 1, Height: 1},
+				{Size: 4, Weight: 4, Length: 1, Width: 1, Height: 1},
+				{Size: 3, Weight: 3, Length: 1, Width: 1, Height: 1},
+				{Size: 2, Weight: 2, Length: 1, Width: 1, Height: 1},
+			},
+			packs:  []domain.PackResult{{Size: 5, Count: 20}, {Size: 4, Count: 20}, {Size: 3, Count: 60}, {Size: 2, Count: 20}},
+			limits: domain.ParcelLimits{MaxWeight: 10},
+			expected: []domain.Parcel{
+				{Count: 10, Packs: []domain.PackResult{{Size: 5, Count: 2}}, Weight: 10, Volume: 2},
+				{Count: 10, Packs: []domain.PackResult{{Size: 4, Count: 2}, {Size: 2, Count: 1}}, Weight: 10, Volume: 3},
+				{Count: 20, Packs: []domain.PackResult{{Size: 3, Count: 3}}, Weight: 9, Volume: 3},
+				{Count: 2, Packs: []domain.PackResult{{Size: 2, Count: 5}}, Weight: 10, Volume: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dimensions := tt.dimensions
+			if dimensions == nil {
+				dimensions = testDimensions
+			}
+			parcels, optimal, err := planParcels(tt.packs, dimensions, tt.limits)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parcels)
+			assert.Equal(t, tt.optimal, optimal)
+		})
+	}
+}
+
+func TestPlanParcels_Errors(t *testing.T) {
+	_, _, err := planParcels([]domain.PackResult{{Size: 1000, Count: 1}}, testDimensions, domain.ParcelLimits{MaxWeight: 1000})
+	assert.ErrorIs(t, err, domain.ErrMissingPackDimensions)
+
+	_, _, err = planParcels([]domain.PackResult{{Size: 5000, Count: 1}}, testDimensions, domain.ParcelLimits{MaxVolume: 10_000})
+	assert.ErrorIs(t, err, domain.ErrPackExceedsParcel)
+	assert.EqualError(t, err, "pack does not fit in a parcel: a pack of 5000 weighs 5000 g and takes 12000 cm³")
+}
+
+func TestPlanParcels_WithinLimits(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		var packs []domain.PackResult
+		var weight, volume int64
+		for _, d := range testDimensions {
+			if n := rng.Int63n(12); n > 0 {
+				packs = append(packs, domain.PackResult{Size: d.Size, Count: n})
+				weight += n * d.Weight
+				volume += n * d.Volume()
+			}
+		}
+		limits := domain.ParcelLimits{MaxWeight: 5000 + rng.Int63n(10_000), MaxVolume: 12_000 + rng.Int63n(30_000)}
+		if rng.Intn(3) == 0 {
+			limits.MaxVolume = 0
+		}
+
+		parcels, optimal, err := planParcels(packs, testDimensions, limits)
+		require.NoError(t, err)
+
+		shipped := make(map[domain.PackSize]int64)
+		var count int64
+		for _, p := range parcels {
+			require.LessOrEqual(t, p.Weight, limits.MaxWeight, "limits %+v", limits)
+			if limits.MaxVolume > 0 {
+				require.LessOrEqual(t, p.Volume, limits.MaxVolume, "limits %+v", limits)
+			}
+			for _, r := range p.Packs {
+				shipped[r.Size] += p.Count * r.Count
+			}
+			count += p.Count
+		}
+		for _, r := range packs {
+			require.Equal(t, r.Count, shipped[r.Size])
+		}
+
+		// No plan needs fewer parcels than the total weight or volume fills.
+		lower := ceilDiv(weight, limits.MaxWeight)
+		if limits.MaxVolume > 0 {
+			lower = max(lower, ceilDiv(volume, limits.MaxVolume))
+		}
+		require.GreaterOrEqual(t, count, lower)
+		if count == lower {
+			require.True(t, optimal, "limits %+v", limits)
+		}
+	}
+}
+
+func TestPlanParcels_Fewest(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 300; i++ {
+		var packs []domain.PackResult
+		var weights []int64
+		for _, d := range testDimensions {
+			n := rng.Int63n(4)
+			if n > 0 {
+				packs = append(packs, domain.PackResult{Size: d.Size, Count: n})
+			}
+			for range n {
+				weights = append(weights, d.Weight)
+			}
+		}
+		if len(packs) == 0 {
+			continue
+		}
+		limits := domain.ParcelLimits{MaxWeight: 5000 + rng.Int63n(6000)}
+
+		parcels, optimal, err := planParcels(packs, testDimensions, limits)
+		require.NoError(t, err)
+		require.True(t, optimal)
+
+		var count int64
+		for _, p := range parcels {
+			count += p.Count
+		}
+		require.Equal(t, fewestBins(weights, limits.MaxWeight), count, "packs %v, limits %+v", packs, limits)
+	}
+}
+
+// fewestBins returns the fewest bins of the capacity the weights fit in, by
+// dynamic programming over the subsets of the weights.
+func fewestBins(weights []int64, capacity int64) int64 {
+	full := 1<<len(weights) - 1
+	fits := make([]bool, full+1)
+	for mask := range fits {
+		var sum int64
+		for i, w := range weights {
+			if mask&(1<<i) != 0 {
+				sum += w
+			}
+		}
+		fits[mask] = sum <= capacity
+	}
+
+	bins := make([]int64, full+1)
+	for mask := 1; mask <= full; mask++ {
+		bins[mask] = math.MaxInt64
+		low := mask & -mask
+		for sub := mask; sub > 0; sub = (sub - 1) & mask {
+			if sub&low != 0 && fits[sub] {
+				bins[mask] = min(bins[mask], bins[mask^sub]+1)
+			}
+		}
+	}
+	return bins[full]
+}
@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"maps"
+)
+
+// SimulatePackSizesUseCase tries a proposed pack set on a workload without
+// storing it.
+type SimulatePackSizesUseCase struct {
+	packs *CalculatePacksUseCase
+}
+
+// NewSimulatePackSizesUseCase reads the current pack set and its tables from
+// packs and shares its compute budget.
+func NewSimulatePackSizesUseCase(packs *CalculatePacksUseCase) *SimulatePackSizesUseCase {
+	return &SimulatePackSizesUseCase{packs: packs}
+}
+
+// Simulate packs the workload under the current and the proposed pack set and
+// lists the orders whose packing changes. The proposal gets its own tables
+// and leaves the shared ones alone, so it can be evaluated before a PUT.
+func (uc *SimulatePackSizesUseCase) Simulate(ctx context.Context, req domain.SimulationRequest) (*domain.Simulation, error) {
+	proposed, err := validatePackSizes(req.PackSizes)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := workloadOrders(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	table, err := newPackTable(ctx, normalizePackSizes(proposed))
+	if err != nil {
+		return nil, err
+	}
+	score, proposedPackings, err := scorePackSet(ctx, table, orders)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.Simulation{Proposed: score, ChangedOrders: []int64{}}
+
+	current := uc.packs.repo.GetPackSizes()
+	if len(current) == 0 {
+		return result, nil
+	}
+	table, err = uc.packs.tables.get(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+	score, currentPackings, err := scorePackSet(ctx, table, orders)
+	if err != nil {
+		return nil, err
+	}
+	result.Current = &score
+
+	for i, order := range orders {
+		if !maps.Equal(currentPackings[i], proposedPackings[i]) {
+			result.ChangedOrders = append(result.ChangedOrders, order.OrderSize)
+		}
+	}
+	return result, nil
+}
+
+// workloadOrders expands the listed orders and the range of a simulation into
+// one workload, with weights defaulting to 1.
+func workloadOrders(req domain.SimulationRequest) ([]domain.WeightedOrder, error) {
+	orders := make([]domain.WeightedOrder, 0, len(req.Orders))
+	for _, order := range req.Orders {
+		if order.Weight == 0 {
+			order.Weight = 1
+		}
+		orders = append(orders, order)
+	}
+
+	if r := req.Range; r != nil {
+		if r.From < 1 || r.To < r.From || r.To-r.From >= maxWorkloadOrders {
+			return nil, domain.ErrInvalidWorkload
+		}
+		weight := r.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		for size := r.From; size <= r.To; size++ {
+			orders = append(orders, domain.WeightedOrder{OrderSize: size, Weight: weight})
+		}
+	}
+
+	return aggregateWorkload(orders, maxWorkloadOrderSize)
+}
@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSimulatePackSizesUseCase_Simulate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return([]domain.PackSize{250, 500})
+
+	cache := NewPackTableCache()
+	uc := NewSimulatePackSizesUseCase(NewCalculatePacksUseCase(mockRepo, cache))
+	result, err := uc.Simulate(context.Background(), domain.SimulationRequest{
+		PackSizes: []domain.PackSize{300, 250},
+		Orders:    []domain.WeightedOrder{{OrderSize: 300, Weight: 3}, {OrderSize: 500}},
+		Range:     &domain.OrderRange{From: 249, To: 250, Weight: 2},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.Simulation{
+		Current: &domain.PackSetScore{
+			PackSizes:  []domain.PackSize{250, 500},
+			TotalItems: 2*250 + 2*250 + 3*500 + 500,
+			Overshoot:  2*1 + 3*200,
+			PackCount:  2 + 2 + 3 + 1,
+			Packs:      []domain.PackResult{{Size: 500, Count: 4}, {Size: 250, Count: 4}},
+		},
+		Proposed: domain.PackSetScore{
+			PackSizes:  []domain.PackSize{250, 300},
+			TotalItems: 2*250 + 2*250 + 3*300 + 500,
+			Overshoot:  2 * 1,
+			PackCount:  2 + 2 + 3 + 2,
+			Packs:      []domain.PackResult{{Size: 300, Count: 3}, {Size: 250, Count: 6}},
+		},
+		ChangedOrders: []int64{300, 500},
+	}, result)
+
+	// The proposal does not replace the shared tables.
+	table := cache.current.Load()
+	require.NotNil(t, table)
+	assert.Equal(t, []int{250, 500}, table.sizes)
+}
+
+func TestSimulatePackSizesUseCase_NoCurrentPackSizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPackSizeRepository(ctrl)
+	mockRepo.EXPECT().GetPackSizes().Return(nil)
+
+	uc := NewSimulatePackSizesUseCase(NewCalculatePacksUseCase(mockRepo, NewPackTableCache()))
+	result, err := uc.Simulate(context.Background(), domain.SimulationRequest{
+		PackSizes: []domain.PackSize{250},
+		Range:     &domain.OrderRange{From: 1, To: 250},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result.Current)
+	assert.Equal(t, int64(250*250), result.Proposed.TotalItems)
+	assert.Empty(t, result.ChangedOrders)
+}
+
+func TestSimulatePackSizesUseCase_Errors(t *testing.T) {
+	uc := NewSimulatePackSizesUseCase(NewCalculatePacksUseCase(nil, NewPackTableCache()))
+	orders := []domain.WeightedOrder{{OrderSize: 100}}
+
+	tests := []struct {
+		name    string
+		req     domain.SimulationRequest
+		wantErr error
+	}{
+		{name: "no pack sizes", req: domain.SimulationRequest{Orders: orders}, wantErr: domain.ErrEmptyPackSizes},
+		{name: "invalid pack size", req: domain.SimulationRequest{PackSizes: []domain.PackSize{0}, Orders: orders}, wantErr: domain.ErrInvalidPackSize},
+		{name: "no orders", req: domain.SimulationRequest{PackSizes: []domain.PackSize{250}}, wantErr: domain.ErrEmptyWorkload},
+		{name: "inverted range", req: domain.SimulationRequest{PackSizes: []domain.PackSize{250}, Range: &domain.OrderRange{From: 10, To: 1}}, wantErr: domain.ErrInvalidWorkload},
+		{name: "range too long", req: domain.SimulationRequest{PackSizes: []domain.PackSize{250}, Range: &domain.OrderRange{From: 1, To: 10_001}}, wantErr: domain.ErrInvalidWorkload},
+		{name: "negative weight", req: domain.SimulationRequest{PackSizes: []domain.PackSize{250}, Orders: []domain.WeightedOrder{{OrderSize: 1, Weight: -1}}}, wantErr: domain.ErrInvalidWorkload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Simulate(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
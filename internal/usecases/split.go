@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// maxSplitWarehouses bounds the warehouses a single split may rank.
+const maxSplitWarehouses = 100
+
+// SplitOrderUseCase splits orders across warehouses, each packing its share
+// with its own pack sizes and stock.
+type SplitOrderUseCase struct {
+	warehouses domain.WarehouseRepository
+	packs      *CalculatePacksUseCase
+	tables     *PackTablesByID // by warehouse ID
+}
+
+// NewSplitOrderUseCase solves shares with packs, sharing its costs, default
+// policy and compute budget. tables holds the warehouses' solver tables and is
+// shared with the WarehousesUseCase that evicts them.
+func NewSplitOrderUseCase(
+	warehouses domain.WarehouseRepository,
+	packs *CalculatePacksUseCase,
+	tables *PackTablesByID,
+) *SplitOrderUseCase {
+	return &SplitOrderUseCase{
+		warehouses: warehouses,
+		packs:      packs,
+		tables:     tables,
+	}
+}
+
+// Execute allocates the quantity to the warehouses in order of preference:
+// each takes what is left if its stock covers it, packed under opts, and
+// otherwise ships all its stock and leaves the rest to the next. So only the
+// last warehouse overshoots. A warehouse whose stock covers the rest but no
+// packing of it fits the overshoot cap in opts ships nothing, and the next
+// one is tried. Without preferences every warehouse is used, in order of ID.
+// The compute budget covers the whole split, and the pack constraints of the
+// main pack set do not apply.
+//
+// When all the warehouses together fall short, the InsufficientStockError
+// lists every pack they hold, unless a warehouse was passed over for the cap:
+// then its OvershootError is returned. Errors from packing a share name its
+// warehouse.
+func (uc *SplitOrderUseCase) Execute(
+	ctx context.Context,
+	quantity int64,
+	preferences []domain.WarehousePreference,
+	opts domain.CalculateOptions,
+) (*domain.WarehouseSplit, error) {
+	if quantity <= 0 {
+		return nil, domain.ErrOrderSizePositive
+	}
+
+	warehouses, err := uc.rank(preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := uc.packs.resolvePolicy(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withComputeBudget(ctx, uc.packs.computeBudget)
+	defer cancel()
+
+	split := &domain.WarehouseSplit{Quantity: quantity, Allocations: []domain.WarehouseAllocation{}}
+	shipped := make(map[int]int64)
+	remaining := quantity
+	var passedOver error
+	for _, w := range warehouses {
+		allocation, err := uc.allocate(ctx, w, remaining, policy, opts)
+		if errors.Is(err, domain.ErrOvershootLimitExceeded) {
+			if passedOver == nil {
+				passedOver = fmt.Errorf("warehouse %s: %w", w.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("warehouse %s: %w", w.ID, err)
+		}
+		if allocation.Quantity == 0 {
+			continue
+		}
+		split.Allocations = append(split.Allocations, allocation)
+		for _, p := range allocation.Packs {
+			shipped[int(p.Size)] += p.Count
+		}
+
+		var ok bool
+		if split.TotalItems, ok = checkedAdd(split.TotalItems, allocation.TotalItems); !ok {
+			return nil, domain.ErrTotalOverflow
+		}
+		split.Overshoot += allocation.Overshoot
+		split.PackCount += allocation.PackCount
+		if remaining -= allocation.Quantity; remaining == 0 {
+			return split, nil
+		}
+	}
+
+	if passedOver != nil {
+		return nil, passedOver
+	}
+	return nil, &domain.InsufficientStockError{
+		OrderSize: quantity,
+		Available: quantity - remaining,
+		Shortfall: remaining,
+		Packs:     toPackResults(shipped),
+	}
+}
+
+// rank returns the warehouses to ship from, most preferred first.
+func (uc *SplitOrderUseCase) rank(preferences []domain.WarehousePreference) ([]domain.Warehouse, error) {
+	if len(preferences) == 0 {
+		warehouses := uc.warehouses.GetWarehouses()
+		if len(warehouses) == 0 {
+			return nil, domain.ErrNoWarehouses
+		}
+		return warehouses, nil
+	}
+	if len(preferences) > maxSplitWarehouses {
+		return nil, domain.ErrInvalidWarehousePreference
+	}
+
+	ranked := make([]domain.WarehousePreference, len(preferences))
+	copy(ranked, preferences)
+	seen := make(map[string]bool, len(ranked))
+	for _, p := range ranked {
+		if p.Cost < 0 || seen[p.WarehouseID] {
+			return nil, domain.ErrInvalidWarehousePreference
+		}
+		seen[p.WarehouseID] = true
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Cost < ranked[j].Cost })
+
+	warehouses := make([]domain.Warehouse, 0, len(ranked))
+	for _, p := range ranked {
+		w, ok := uc.warehouses.GetWarehouse(p.WarehouseID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrWarehouseNotFound, p.WarehouseID)
+		}
+		warehouses = append(warehouses, w)
+	}
+	return warehouses, nil
+}
+
+// allocate packs up to remaining items from the warehouse: all of them if its
+// stock covers them, else all its stock. An allocation of no items means the
+// warehouse has nothing to ship.
+func (uc *SplitOrderUseCase) allocate(
+	ctx context.Context,
+	w domain.Warehouse,
+	remaining int64,
+	policy Policy,
+	opts domain.CalculateOptions,
+) (domain.WarehouseAllocation, error) {
+	table, err := uc.tables.get(w.ID).get(ctx, w.PackSizes)
+	if err != nil {
+		return domain.WarehouseAllocation{}, err
+	}
+
+	st := &packState{table: table, stock: w.Stock}
+	if usesCosts(policy) {
+		st.costs = uc.packs.repo.GetCosts()
+	}
+	// A warehouse always ships within its own stock; without any, the
+	// residue tables can answer.
+	opts.RespectStock = len(w.Stock) > 0
+
+	sol, err := uc.packs.solve(ctx, remaining, st, policy, opts)
+	var stockErr *domain.InsufficientStockError
+	if errors.As(err, &stockErr) {
+		var packCount int64
+		for _, p := range stockErr.Packs {
+			packCount += p.Count
+		}
+		return domain.WarehouseAllocation{
+			WarehouseID: w.ID,
+			Quantity:    stockErr.Available,
+			Packs:       stockErr.Packs,
+			TotalItems:  stockErr.Available,
+			PackCount:   packCount,
+		}, nil
+	}
+	if err != nil {
+		return domain.WarehouseAllocation{}, err
+	}
+
+	return domain.WarehouseAllocation{
+		WarehouseID: w.ID,
+		Quantity:    remaining,
+		Packs:       toPackResults(sol.counts),
+		TotalItems:  sol.total,
+		Overshoot:   sol.total - remaining,
+		PackCount:   sumCounts(sol.counts),
+	}, nil
+}
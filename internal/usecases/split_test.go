@@ -0,0 +1,184 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func splitWarehouses(ctrl *gomock.Controller) *mocks.MockWarehouseRepository {
+	repo := mocks.NewMockWarehouseRepository(ctrl)
+	warehouses := []domain.Warehouse{
+		{ID: "east", PackSizes: []domain.PackSize{250, 500, 1000}},
+		{ID: "north", PackSizes: []domain.PackSize{300, 700}, Stock: []domain.PackStock{{Size: 300, Available: 2}, {Size: 700, Available: 1}}},
+		{ID: "south", PackSizes: []domain.PackSize{100}, Stock: []domain.PackStock{{Size: 100, Available: 0}}},
+	}
+	repo.EXPECT().GetWarehouses().Return(warehouses).AnyTimes()
+	for _, w := range warehouses {
+		repo.EXPECT().GetWarehouse(w.ID).Return(w, true).AnyTimes()
+	}
+	repo.EXPECT().GetWarehouse(gomock.Any()).Return(domain.Warehouse{}, false).AnyTimes()
+	return repo
+}
+
+func TestSplitOrderUseCase_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := NewSplitOrderUseCase(splitWarehouses(ctrl), NewCalculatePacksUseCase(mocks.NewMockPackSizeRepository(ctrl), NewPackTableCache()), NewPackTablesByID(10))
+
+	t.Run("preferred warehouse ships all its stock first", func(t *testing.T) {
+		split, err := uc.Execute(context.Background(), 1600, []domain.WarehousePreference{
+			{WarehouseID: "east", Cost: 5},
+			{WarehouseID: "north", Cost: 1},
+			{WarehouseID: "south", Cost: 1},
+		}, domain.CalculateOptions{})
+		require.NoError(t, err)
+
+		// north holds 1300 items and south none, so east ships the last 300.
+		assert.Equal(t, &domain.WarehouseSplit{
+			Quantity: 1600,
+			Allocations: []domain.WarehouseAllocation{
+				{WarehouseID: "north", Quantity: 1300, Packs: []domain.PackResult{{Size: 700, Count: 1}, {Size: 300, Count: 2}}, TotalItems: 1300, PackCount: 3},
+				{WarehouseID: "east", Quantity: 300, Packs: []domain.PackResult{{Size: 500, Count: 1}}, TotalItems: 500, Overshoot: 200, PackCount: 1},
+			},
+			TotalItems: 1800,
+			Overshoot:  200,
+			PackCount:  4,
+		}, split)
+	})
+
+	t.Run("stock covers the order", func(t *testing.T) {
+		split, err := uc.Execute(context.Background(), 650, []domain.WarehousePreference{
+			{WarehouseID: "north"},
+			{WarehouseID: "east"},
+		}, domain.CalculateOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, &domain.WarehouseSplit{
+			Quantity: 650,
+			Allocations: []domain.WarehouseAllocation{
+				{WarehouseID: "north", Quantity: 650, Packs: []domain.PackResult{{Size: 700, Count: 1}}, TotalItems: 700, Overshoot: 50, PackCount: 1},
+			},
+			TotalItems: 700,
+			Overshoot:  50,
+			PackCount:  1,
+		}, split)
+	})
+
+	t.Run("a warehouse that cannot meet the cap is passed over", func(t *testing.T) {
+		prefs := []domain.WarehousePreference{{WarehouseID: "north"}, {WarehouseID: "east"}}
+		opts := domain.CalculateOptions{ExactOnly: true}
+
+		// north holds 750 items but cannot ship exactly 750, so east does.
+		split, err := uc.Execute(context.Background(), 750, prefs, opts)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WarehouseSplit{
+			Quantity: 750,
+			Allocations: []domain.WarehouseAllocation{
+				{WarehouseID: "east", Quantity: 750, Packs: []domain.PackResult{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, TotalItems: 750, PackCount: 2},
+			},
+			TotalItems: 750,
+			PackCount:  2,
+		}, split)
+
+		// Neither ships exactly 650, so the first warehouse passed over reports.
+		_, err = uc.Execute(context.Background(), 650, prefs, opts)
+		var overshootErr *domain.OvershootError
+		require.ErrorAs(t, err, &overshootErr)
+		assert.ErrorContains(t, err, "warehouse north")
+		assert.Equal(t, int64(600), overshootErr.NearestBelow)
+		assert.Equal(t, int64(700), overshootErr.NearestAbove)
+	})
+
+	t.Run("all warehouses by ID without preferences", func(t *testing.T) {
+		split, err := uc.Execute(context.Background(), 1001, nil, domain.CalculateOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, split.Allocations, 1)
+		assert.Equal(t, "east", split.Allocations[0].WarehouseID)
+		assert.Equal(t, int64(1250), split.TotalItems)
+	})
+
+	t.Run("warehouses fall short", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), 2000, []domain.WarehousePreference{
+			{WarehouseID: "north"},
+			{WarehouseID: "south"},
+		}, domain.CalculateOptions{})
+
+		var stockErr *domain.InsufficientStockError
+		require.ErrorAs(t, err, &stockErr)
+		assert.Equal(t, &domain.InsufficientStockError{
+			OrderSize: 2000,
+			Available: 1300,
+			Shortfall: 700,
+			Packs:     []domain.PackResult{{Size: 700, Count: 1}, {Size: 300, Count: 2}},
+		}, stockErr)
+	})
+
+	t.Run("errors name the warehouse", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), 1001, []domain.WarehousePreference{{WarehouseID: "east"}}, domain.CalculateOptions{ExactOnly: true})
+		assert.ErrorIs(t, err, domain.ErrOvershootLimitExceeded)
+		assert.ErrorContains(t, err, "warehouse east: ")
+	})
+}
+
+func TestSplitOrderUseCase_ExecuteInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		quantity    int64
+		preferences []domain.WarehousePreference
+		wantErr     error
+	}{
+		{
+			name:     "quantity must be positive",
+			quantity: 0,
+			wantErr:  domain.ErrOrderSizePositive,
+		},
+		{
+			name:        "unknown warehouse",
+			quantity:    10,
+			preferences: []domain.WarehousePreference{{WarehouseID: "west"}},
+			wantErr:     domain.ErrWarehouseNotFound,
+		},
+		{
+			name:        "duplicate warehouse",
+			quantity:    10,
+			preferences: []domain.WarehousePreference{{WarehouseID: "east"}, {WarehouseID: "east", Cost: 2}},
+			wantErr:     domain.ErrInvalidWarehousePreference,
+		},
+		{
+			name:        "negative cost",
+			quantity:    10,
+			preferences: []domain.WarehousePreference{{WarehouseID: "east", Cost: -1}},
+			wantErr:     domain.ErrInvalidWarehousePreference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := NewSplitOrderUseCase(splitWarehouses(ctrl), NewCalculatePacksUseCase(mocks.NewMockPackSizeRepository(ctrl), NewPackTableCache()), NewPackTablesByID(10))
+			_, err := uc.Execute(context.Background(), tt.quantity, tt.preferences, domain.CalculateOptions{})
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("no warehouses", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockWarehouses := mocks.NewMockWarehouseRepository(ctrl)
+		mockWarehouses.EXPECT().GetWarehouses().Return([]domain.Warehouse{})
+		uc := NewSplitOrderUseCase(mockWarehouses, NewCalculatePacksUseCase(mocks.NewMockPackSizeRepository(ctrl), NewPackTableCache()), NewPackTablesByID(10))
+		_, err := uc.Execute(context.Background(), 10, nil, domain.CalculateOptions{})
+		assert.ErrorIs(t, err, domain.ErrNoWarehouses)
+	})
+}
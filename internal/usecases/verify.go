@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// verifyNodeBudget caps the branches the verifier's search explores before it
+// gives up with domain.ErrVerificationInconclusive.
+const verifyNodeBudget = 1 << 22
+
+// VerifyPacking checks packs against the FewestItems rules for the pack sizes:
+// whole packs of those sizes covering orderSize, with the fewest items and,
+// for those, the fewest packs. The optimum comes from a branch-and-bound
+// search over the pack counts that shares nothing with the residue tables, so
+// it can catch mistakes in how they pre-allocate the largest packs.
+//
+// It returns domain.ErrInvalidPacking for packs that are not a packing of the
+// order, domain.ErrPackingNotOptimal when a better one exists, and
+// domain.ErrVerificationInconclusive when the search runs out of budget.
+func VerifyPacking(
+	ctx context.Context,
+	orderSize int64,
+	packSizes []domain.PackSize,
+	packs []domain.PackResult,
+) error {
+	if orderSize <= 0 {
+		return domain.ErrOrderSizePositive
+	}
+	sizes := normalizePackSizes(packSizes)
+	if len(sizes) == 0 || sizes[0] <= 0 {
+		return domain.ErrNoPackSizes
+	}
+	return verifyPacking(ctx, orderSize, sizes, packs)
+}
+
+// verifyPacking is VerifyPacking for ascending, distinct, positive sizes.
+func verifyPacking(ctx context.Context, orderSize int64, sizes []int, packs []domain.PackResult) error {
+	var total, count int64
+	for _, p := range packs {
+		if p.Count <= 0 {
+			return fmt.Errorf("%w: %d packs of %d", domain.ErrInvalidPacking, p.Count, p.Size)
+		}
+		if _, ok := slices.BinarySearch(sizes, int(p.Size)); !ok {
+			return fmt.Errorf("%w: no pack size %d", domain.ErrInvalidPacking, p.Size)
+		}
+		items, ok := checkedMul(p.Count, int64(p.Size))
+		if ok {
+			total, ok = checkedAdd(total, items)
+		}
+		if !ok {
+			return fmt.Errorf("%w: total exceeds the 64-bit range", domain.ErrInvalidPacking)
+		}
+		count += p.Count
+	}
+	if total < orderSize {
+		return fmt.Errorf("%w: %d items ship for an order of %d", domain.ErrInvalidPacking, total, orderSize)
+	}
+
+	wantTotal, wantPacks, err := searchOptimum(ctx, orderSize, sizes)
+	if err != nil {
+		return err
+	}
+	if total != wantTotal || count != wantPacks {
+		return fmt.Errorf("%w: %d items in %d packs, %d items in %d packs possible",
+			domain.ErrPackingNotOptimal, total, count, wantTotal, wantPacks)
+	}
+	return nil
+}
+
+// branchAndBound searches pack counts from the largest size down for the
+// fewest items, then the fewest packs, that cover an order.
+type branchAndBound struct {
+	ctx   context.Context
+	sizes []int64 // descending
+	// gcds[i] divides every amount sizes[i:] can make.
+	gcds      []int64
+	nodes     int
+	bestTotal int64
+	bestPacks int64
+}
+
+// searchOptimum returns the fewest items whole packs of the ascending sizes
+// ship for the order and the fewest packs that ship them.
+func searchOptimum(ctx context.Context, orderSize int64, sizes []int) (total, packs int64, err error) {
+	largest := int64(sizes[len(sizes)-1])
+	if orderSize > math.MaxInt64-largest {
+		// Totals up to orderSize plus the largest pack must fit.
+		return 0, 0, fmt.Errorf("%w: order too large", domain.ErrVerificationInconclusive)
+	}
+
+	s := &branchAndBound{ctx: ctx, sizes: make([]int64, len(sizes)), gcds: make([]int64, len(sizes))}
+	for i, size := range sizes {
+		s.sizes[len(sizes)-1-i] = int64(size)
+	}
+	g := 0
+	for i := len(sizes) - 1; i >= 0; i-- {
+		g = gcd(g, int(s.sizes[i]))
+		s.gcds[i] = int64(g)
+	}
+
+	// Largest packs alone are always a packing; the search only improves on it.
+	n := ceilDiv(orderSize, largest)
+	s.bestTotal, s.bestPacks = n*largest, n
+
+	if err := s.search(0, orderSize, 0, 0); err != nil {
+		return 0, 0, err
+	}
+	return s.bestTotal, s.bestPacks, nil
+}
+
+func (s *branchAndBound) better(total, packs int64) bool {
+	return total < s.bestTotal || (total == s.bestTotal && packs < s.bestPacks)
+}
+
+// search picks the counts of sizes[i:] for the remaining items, with total
+// items in packs packs already chosen.
+func (s *branchAndBound) search(i int, remaining, total, packs int64) error {
+	s.nodes++
+	if s.nodes > verifyNodeBudget {
+		return fmt.Errorf("%w: more than %d branches", domain.ErrVerificationInconclusive, verifyNodeBudget)
+	}
+	if s.nodes%cancelCheckInterval == 0 {
+		if err := contextError(s.ctx); err != nil {
+			return err
+		}
+	}
+
+	if remaining <= 0 {
+		if s.better(total, packs) {
+			s.bestTotal, s.bestPacks = total, packs
+		}
+		return nil
+	}
+	if i == len(s.sizes) {
+		return nil
+	}
+
+	// Nothing below here ships less than the next multiple of gcds[i], or in
+	// fewer packs than sizes[i] alone needs.
+	size := s.sizes[i]
+	minTotal := total + ceilDiv(remaining, s.gcds[i])*s.gcds[i]
+	if !s.better(minTotal, packs+ceilDiv(remaining, size)) {
+		return nil
+	}
+	if i+1 == len(s.sizes) {
+		// The smallest size has to cover the rest on its own.
+		n := ceilDiv(remaining, size)
+		return s.search(i+1, remaining-n*size, total+n*size, packs+n)
+	}
+
+	for c := ceilDiv(remaining, size); c >= 0; c-- {
+		if err := s.search(i+1, remaining-c*size, total+c*size, packs+c); err != nil {
+			return err
+		}
+		if minTotal < s.bestTotal {
+			continue
+		}
+		// Only ties on items can still win. Each pack of sizes[i] dropped
+		// takes at least one pack of the smaller sizes to replace, so once
+		// the fewest packs possible with c-1 reach the best, no smaller count
+		// does better.
+		rest := remaining - (c-1)*size
+		if packs+c-1+ceilDiv(rest, s.sizes[i+1]) >= s.bestPacks {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"context"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchOptimum_MatchesNaiveDP(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 200; i++ {
+		sizes := make([]int, 1+rng.Intn(4))
+		for j := range sizes {
+			sizes[j] = 1 + rng.Intn(60)
+		}
+		sizes = slices.Compact(slices.Sorted(slices.Values(sizes)))
+
+		for orderSize := int64(1); orderSize <= 400; orderSize += 1 + rng.Int63n(7) {
+			wantTotal, wantPacks := naiveOptimum(orderSize, sizes)
+			gotTotal, gotPacks, err := searchOptimum(context.Background(), orderSize, sizes)
+			require.NoError(t, err)
+
+			require.Equal(t, wantTotal, gotTotal, "sizes %v, order %d", sizes, orderSize)
+			require.Equal(t, wantPacks, gotPacks, "sizes %v, order %d", sizes, orderSize)
+		}
+	}
+}
+
+func TestVerifyPacking_AcceptsCalculatedPackings(t *testing.T) {
+	tests := []struct {
+		sizes  []int
+		orders []int64
+	}{
+		{sizes: []int{250, 500, 1000, 2000, 5000}, orders: []int64{1, 250, 251, 501, 12001, 1_000_000_007}},
+		{sizes: []int{23, 31, 53}, orders: []int64{1, 263, 500_000, 1_000_000_000_000}},
+		{sizes: []int{3, 7, 997}, orders: []int64{8, 1994, 123_456_789}},
+		{sizes: []int{999_979, 999_983}, orders: []int64{1, 999_980, 1_000_000_000}},
+	}
+
+	for _, tt := range tests {
+		table := mustPackTable(t, tt.sizes...)
+		packSizes := make([]domain.PackSize, len(tt.sizes))
+		for i, size := range tt.sizes {
+			packSizes[i] = domain.PackSize(size)
+		}
+		for _, orderSize := range tt.orders {
+			counts, err := calculateOptimalPacks(context.Background(), orderSize, table)
+			require.NoError(t, err)
+
+			err = VerifyPacking(context.Background(), orderSize, packSizes, toPackResults(counts))
+			assert.NoError(t, err, "sizes %v, order %d", tt.sizes, orderSize)
+		}
+	}
+}
+
+func TestVerifyPacking_Rejects(t *testing.T) {
+	sizes := []domain.PackSize{250, 500, 1000, 2000, 5000}
+
+	tests := []struct {
+		name      string
+		orderSize int64
+		packs     []domain.PackResult
+		wantErr   error
+		wantMsg   string
+	}{
+		{
+			name:      "more items than needed",
+			orderSize: 251,
+			packs:     []domain.PackResult{{Size: 1000, Count: 1}},
+			wantErr:   domain.ErrPackingNotOptimal,
+			wantMsg:   "packing is not optimal: 1000 items in 1 packs, 500 items in 1 packs possible",
+		},
+		{
+			name:      "more packs than needed",
+			orderSize: 501,
+			packs:     []domain.PackResult{{Size: 250, Count: 3}},
+			wantErr:   domain.ErrPackingNotOptimal,
+			wantMsg:   "packing is not optimal: 750 items in 3 packs, 750 items in 2 packs possible",
+		},
+		{
+			name:      "short of the order",
+			orderSize: 501,
+			packs:     []domain.PackResult{{Size: 500, Count: 1}},
+			wantErr:   domain.ErrInvalidPacking,
+		},
+		{
+			name:      "unknown size",
+			orderSize: 250,
+			packs:     []domain.PackResult{{Size: 300, Count: 1}},
+			wantErr:   domain.ErrInvalidPacking,
+		},
+		{
+			name:      "empty pack count",
+			orderSize: 250,
+			packs:     []domain.PackResult{{Size: 250, Count: 1}, {Size: 500, Count: 0}},
+			wantErr:   domain.ErrInvalidPacking,
+		},
+		{
+			name:      "total overflows",
+			orderSize: 250,
+			packs:     []domain.PackResult{{Size: 5000, Count: math.MaxInt64 / 1000}},
+			wantErr:   domain.ErrInvalidPacking,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyPacking(context.Background(), tt.orderSize, sizes, tt.packs)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyPacking_InvalidInputs(t *testing.T) {
+	err := VerifyPacking(context.Background(), 0, []domain.PackSize{250}, nil)
+	assert.ErrorIs(t, err, domain.ErrOrderSizePositive)
+
+	err = VerifyPacking(context.Background(), 250, nil, nil)
+	assert.ErrorIs(t, err, domain.ErrNoPackSizes)
+}
+
+// Three large sizes and an order below where every total can be shipped leave
+// far more branches than the budget.
+var (
+	hardSizes = []domain.PackSize{999_961, 999_979, 999_983}
+	hardOrder = int64(499_981_000_178)
+	hardPacks = []domain.PackResult{{Size: 999_983, Count: 499_990}}
+)
+
+func TestVerifyPacking_Inconclusive(t *testing.T) {
+	err := VerifyPacking(context.Background(), hardOrder, hardSizes, hardPacks)
+	assert.ErrorIs(t, err, domain.ErrVerificationInconclusive)
+
+	// 7 divides math.MaxInt64, so whole packs reach it, but not one pack more.
+	err = VerifyPacking(context.Background(), math.MaxInt64-3, []domain.PackSize{7}, []domain.PackResult{{Size: 7, Count: math.MaxInt64 / 7}})
+	assert.ErrorIs(t, err, domain.ErrVerificationInconclusive)
+}
+
+func TestVerifyPacking_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := VerifyPacking(ctx, hardOrder, hardSizes, hardPacks)
+	assert.ErrorIs(t, err, domain.ErrCalculationCanceled)
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import "calculate_product_packs/internal/domain"
+
+type WarehousesUseCase struct {
+	repo   domain.WarehouseRepository
+	tables *PackTablesByID
+}
+
+// NewWarehousesUseCase evicts a warehouse's tables whenever it is saved or
+// deleted.
+func NewWarehousesUseCase(repo domain.WarehouseRepository, tables *PackTablesByID) *WarehousesUseCase {
+	return &WarehousesUseCase{repo: repo, tables: tables}
+}
+
+// SaveWarehouse adds a warehouse or replaces its pack sizes and stock. They
+// follow the same rules as UpdatePackSizes and UpdateStock.
+func (uc *WarehousesUseCase) SaveWarehouse(warehouse domain.Warehouse) error {
+	if !idPattern.MatchString(warehouse.ID) {
+		return domain.ErrInvalidWarehouseID
+	}
+
+	sizes, err := validatePackSizes(warehouse.PackSizes)
+	if err != nil {
+		return err
+	}
+	stock, err := validateStock(warehouse.Stock)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.SaveWarehouse(domain.Warehouse{ID: warehouse.ID, PackSizes: sizes, Stock: stock}); err != nil {
+		return err
+	}
+	uc.tables.evict(warehouse.ID)
+	return nil
+}
+
+func (uc *WarehousesUseCase) GetWarehouses() []domain.Warehouse {
+	return uc.repo.GetWarehouses()
+}
+
+func (uc *WarehousesUseCase) DeleteWarehouse(id string) error {
+	if err := uc.repo.DeleteWarehouse(id); err != nil {
+		return err
+	}
+	uc.tables.evict(id)
+	return nil
+}
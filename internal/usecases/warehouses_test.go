@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"calculate_product_packs/internal/domain"
+	"calculate_product_packs/internal/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWarehousesUseCase_SaveWarehouse(t *testing.T) {
+	tests := []struct {
+		name      string
+		warehouse domain.Warehouse
+		wantErr   error
+		stored    *domain.Warehouse
+	}{
+		{
+			name: "sizes and stock are sorted",
+			warehouse: domain.Warehouse{
+				ID:        "north",
+				PackSizes: []domain.PackSize{500, 250, 500},
+				Stock:     []domain.PackStock{{Size: 500, Available: 2}, {Size: 250, Available: 0}},
+			},
+			stored: &domain.Warehouse{
+				ID:        "north",
+				PackSizes: []domain.PackSize{250, 500},
+				Stock:     []domain.PackStock{{Size: 250, Available: 0}, {Size: 500, Available: 2}},
+			},
+		},
+		{
+			name:      "invalid ID",
+			warehouse: domain.Warehouse{ID: "a/b", PackSizes: []domain.PackSize{250}},
+			wantErr:   domain.ErrInvalidWarehouseID,
+		},
+		{
+			name:      "no sizes",
+			warehouse: domain.Warehouse{ID: "north"},
+			wantErr:   domain.ErrEmptyPackSizes,
+		},
+		{
+			name:      "negative stock",
+			warehouse: domain.Warehouse{ID: "north", PackSizes: []domain.PackSize{250}, Stock: []domain.PackStock{{Size: 250, Available: -1}}},
+			wantErr:   domain.ErrInvalidStock,
+		},
+		{
+			name: "duplicate stock size",
+			warehouse: domain.Warehouse{
+				ID:        "north",
+				PackSizes: []domain.PackSize{250},
+				Stock:     []domain.PackStock{{Size: 250, Available: 1}, {Size: 250, Available: 2}},
+			},
+			wantErr: domain.ErrInvalidStock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockWarehouseRepository(ctrl)
+			if tt.stored != nil {
+				mockRepo.EXPECT().SaveWarehouse(*tt.stored).Return(nil)
+			}
+
+			err := NewWarehousesUseCase(mockRepo, NewPackTablesByID(10)).SaveWarehouse(tt.warehouse)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWarehousesUseCase_EvictsTables(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockWarehouseRepository(ctrl)
+	mockRepo.EXPECT().SaveWarehouse(domain.Warehouse{ID: "east", PackSizes: []domain.PackSize{250}, Stock: []domain.PackStock{{Size: 250, Available: 1}}}).Return(nil)
+	mockRepo.EXPECT().DeleteWarehouse("west").Return(nil)
+	mockRepo.EXPECT().DeleteWarehouse("north").Return(domain.ErrWarehouseNotFound)
+
+	tables := NewPackTablesByID(10)
+	east, west, north := tables.get("east"), tables.get("west"), tables.get("north")
+	uc := NewWarehousesUseCase(mockRepo, tables)
+
+	require.NoError(t, uc.SaveWarehouse(domain.Warehouse{ID: "east", PackSizes: []domain.PackSize{250}, Stock: []domain.PackStock{{Size: 250, Available: 1}}}))
+	require.NoError(t, uc.DeleteWarehouse("west"))
+	assert.ErrorIs(t, uc.DeleteWarehouse("north"), domain.ErrWarehouseNotFound)
+
+	assert.Equal(t, 1, tables.len())
+	assert.NotSame(t, east, tables.get("east"))
+	assert.NotSame(t, west, tables.get("west"))
+	assert.Same(t, north, tables.get("north"))
+}